@@ -3,276 +3,194 @@ package Analyzer
 import (
 	"backend/DiskManagement"
 	"backend/FileSystem"
+	"backend/Structs"
 	"backend/User"
+	"backend/Utilities"
 	"errors"
-	"flag"
 	"fmt"
 	"os"
 	"os/exec"
-	"regexp"
-	"strings"
 )
 
-var re = regexp.MustCompile(`-(\w+)=("[^"]+"|\S+)`)
+// Nombres de reporte que acepta el comando rep
+var validReports = []string{"mbr", "disk", "inode", "block", "bm_inode", "bm_block", "sb", "file", "ls"}
+
+// Options controla cómo se validan y ejecutan los comandos
+type Options struct {
+	Strict      bool                   // true: parámetros desconocidos son error; false: se ignoran con una advertencia
+	StopOnError bool                   // true: un script se detiene en la primera línea que falla
+	Atomic      bool                   // true: si alguna línea falla se deshacen todos los cambios del script
+	DryRun      bool                   // true: se valida y simula el script sin crear ni modificar discos
+	OnResult    func(Result)           // Si no es nil, recibe cada resultado en cuanto termina su línea
+	OnProgress  Utilities.ProgressFunc // Si no es nil, recibe el avance de mkdisk y mkfs
+	Cancel      <-chan struct{}        // Si se cierra, el script se detiene antes de la siguiente línea
+}
+
+// DefaultOptions son las opciones que usa Analyzer. Los parámetros desconocidos solo generan una
+// advertencia para que los scripts antiguos sigan funcionando; el modo estricto se pide aparte.
+var DefaultOptions = Options{Strict: false}
 
 func Analyzer(input string) (string, error) {
-	// Divide la entrada en tokens usando espacios en blanco como delimitadores
-	tokens := strings.Fields(input)
+	return AnalyzerWithOptions(input, DefaultOptions)
+}
 
-	// Si no se proporcionó ningún comando, devuelve un error
-	if len(tokens) == 0 {
-		return "", errors.New("no se proporcionó ningún comando")
-	}
-
-	switch tokens[0] {
-	case "mkdisk":
-		return fn_mkdisk(tokens[1:])
-	case "rmdisk":
-		return fn_rmdisk(tokens[1:])
-	case "fdisk":
-		return fn_fdisk(tokens[1:])
-	case "mount":
-		return fn_mount(tokens[1:])
-	case "mkfs":
-		return fn_mkfs(tokens[1:])
-	case "login":
-		return fn_login(tokens[1:])
-	case "rep":
-		return fn_rep(tokens[1:])
-	case "logout":
-		return User.Logout()
-	case "mkfile":
-		return FileSystem.ParserMkfile(tokens[1:])
-	case "mkdir":
-		return fn_mkdir(tokens[1:])
-	case "cat":
-		return FileSystem.Cat(tokens[1:])
-	case "clear":
-		// Crea un comando para limpiar la terminal
-		cmd := exec.Command("clear")
-		cmd.Stdout = os.Stdout // Redirige la salida del comando a la salida estándar
-		err := cmd.Run()       // Ejecuta el comando
-		if err != nil {
-			// Si hay un error al ejecutar el comando, devuelve un error
-			return "", errors.New("no se pudo limpiar la terminal")
-		}
-		return "", nil // Devuelve nil si el comando se ejecutó correctamente
+// AnalyzerWithOptions analiza y ejecuta una línea con las opciones indicadas
+func AnalyzerWithOptions(input string, opts Options) (string, error) {
+	spec, cmd, warnings, err := prepare(input, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var message string
+	switch {
+	case spec.Name == "execute":
+		// Igual que dentro de un script, execute usa las opciones activas para sus líneas
+		message, err = executeLine(cmd, opts)
+	case opts.DryRun && spec.DryRun == nil:
+		err = fmt.Errorf("el comando %s solo se puede simular dentro de un script", spec.Name)
+	case opts.DryRun:
+		message, err = spec.DryRun(newDryRunState(), cmd)
 	default:
-		// Si el comando no es reconocido, devuelve un error
-		return "", fmt.Errorf("comando desconocido: %s", tokens[0])
+		message, err = runCommand(spec, cmd, InputName)
 	}
+	return withWarnings(message, warnings), err
 }
 
-func fn_mkdisk(tokens []string) (string, error) {
-	fs := flag.NewFlagSet("mkdisk", flag.ExitOnError)
-	size := fs.Int("size", 0, "Tamaño")
-	fit := fs.String("fit", "ff", "Ajuste")
-	unit := fs.String("unit", "m", "Unidad")
-	path := fs.String("path", "", "Ruta")
-
-	// Parse flag
-	fs.Parse(os.Args[1:])
-
-	// Encontrar la flag en el input
-	matches := re.FindAllStringSubmatch(strings.Join(tokens, " "), -1)
-
-	// Process the input
-	for _, match := range matches {
-		flagName := match[1]                   // match[1]: Captura y guarda el nombre del flag (por ejemplo, "size", "unit", "fit", "path")
-		flagValue := strings.ToLower(match[2]) // Captura y guarda el valor del flag
-
-		flagValue = strings.Trim(flagValue, "\"")
-
-		switch flagName {
-		case "size", "fit", "unit":
-			flagValue = strings.ToLower(flagValue)
-			fs.Set(flagName, flagValue)
-		case "path":
-			fs.Set(flagName, flagValue)
-		default:
-			fmt.Println("Error: Flag not found")
-		}
+// prepare tokeniza la línea, busca el comando en el registro y valida sus parámetros
+func prepare(input string, opts Options) (*CommandSpec, Structs.Command, []string, error) {
+	// Tokeniza la entrada respetando comillas, escapes y comentarios
+	cmd, err := Lex(input)
+	if err != nil {
+		return nil, cmd, nil, err
 	}
-	/*
-			Primera Iteración :
-		    flagName es "size".
-		    flagValue es "3000".
-		    El switch encuentra que "size" es un flag reconocido, por lo que se ejecuta fs.Set("size", "3000").
-		    Esto asigna el valor 3000 al flag size.
-
-	*/
-
-	// Validaciones
-	if *size <= 0 {
-		fmt.Println("Error: Size must be greater than 0")
-		return "", fmt.Errorf("parámetro inválido: %v", *size)
 
+	// Si no se proporcionó ningún comando, devuelve un error
+	if cmd.Name == "" {
+		return nil, cmd, nil, errors.New("no se proporcionó ningún comando")
 	}
 
-	if *fit != "bf" && *fit != "ff" && *fit != "wf" {
-		fmt.Println("Error: Fit must be 'bf', 'ff', or 'wf'")
-		return "", fmt.Errorf("parámetro inválido: %s", *fit)
-
+	// Buscar el comando en el registro
+	spec := findCommand(cmd.Name)
+	if spec == nil {
+		// Si el comando no es reconocido, devuelve un error con la sugerencia más cercana
+		return nil, cmd, nil, fmt.Errorf("comando desconocido: %s%s", cmd.Name, didYouMean(cmd.Name, commandNames(), ""))
 	}
 
-	if *unit != "k" && *unit != "m" {
-		fmt.Println("Error: Unit must be 'k' or 'm'")
-		return "", fmt.Errorf("parámetro inválido: %s", *unit)
-
+	// Validar los parámetros contra la definición del comando
+	cmd, warnings, err := validate(spec, cmd, opts)
+	if err != nil {
+		return nil, cmd, nil, err
 	}
+	return spec, cmd, warnings, nil
+}
 
-	if *path == "" {
-		fmt.Println("Error: Path is required")
-		return "", fmt.Errorf("parámetro inválido: %s", *path)
-
+// withWarnings antepone las advertencias del modo no estricto al resultado
+func withWarnings(message string, warnings []string) string {
+	for i := len(warnings) - 1; i >= 0; i-- {
+		message = "Advertencia: " + warnings[i] + "\n" + message
 	}
+	return message
+}
 
-	// LLamamos a la funcion
+func fn_mkdisk(cmd Structs.Command) (string, error) {
 	// Llamar a la función Mkdisk y capturar el mensaje de éxito
-	message, err := DiskManagement.Mkdisk(*size, *fit, *unit, *path)
+	message, err := DiskManagement.Mkdisk(cmd.Int("size"), cmd.Value("fit"), cmd.Value("unit"), cmd.Value("path"), cmd.Value("zero"), cmd.Value("format"), cmd.Value("table"), cmd.Int("entries"))
 	if err != nil {
 		return "", err
 	}
 	return message, nil
 }
 
-func fn_rmdisk(tokens []string) (string, error) {
-	fs := flag.NewFlagSet("rmdisk", flag.ExitOnError)
-	path := fs.String("path", "", "Ruta del disco a eliminar")
-
-	// Parse flag
-	fs.Parse(os.Args[1:])
-
-	// Encontrar el flag en el input
-	matches := re.FindAllStringSubmatch(strings.Join(tokens, " "), -1)
+func fn_convertdisk(cmd Structs.Command) (string, error) {
+	return DiskManagement.ConvertDisk(cmd.Value("path"))
+}
 
-	// Procesar los parámetros del comando
-	for _, match := range matches {
-		flagName := match[1]
-		flagValue := strings.Trim(match[2], "\"")
+func fn_exportmbr(cmd Structs.Command) (string, error) {
+	return DiskManagement.ExportMBR(cmd.Value("path"), cmd.Value("image"))
+}
 
-		if flagName == "path" {
-			fs.Set(flagName, flagValue)
-		} else {
-			fmt.Println("Error: Flag no encontrado:", flagName)
-		}
-	}
+func fn_importmbr(cmd Structs.Command) (string, error) {
+	return DiskManagement.ImportMBR(cmd.Value("image"), cmd.Value("path"))
+}
 
-	// Validar el parámetro de la ruta
-	if *path == "" {
-		fmt.Println("Error: Path es requerido")
-		return "", fmt.Errorf("parámetro inválido: %s", *path)
+func fn_rmdisk(cmd Structs.Command) (string, error) {
+	if err := rmdiskConfirm(cmd); err != nil {
+		return "", err
 	}
-
-	// Llamar a la función que elimina el disco y capturar su retorno
-	message, err := DiskManagement.Rmdisk(*path)
+	message, err := DiskManagement.Rmdisk(cmd.Value("path"), cmd.Has("force"))
 	if err != nil {
-		// Retornar el mensaje y error de la función Rmdisk
 		return message, err
 	}
 
+	// Con -force se desmontan las particiones del disco; si la sesión estaba en una, se cierra
+	if User.CurrentLoggedPartitionID != "" && DiskManagement.GetPartitionByID(User.CurrentLoggedPartitionID) == nil {
+		User.CurrentLoggedPartitionID = ""
+		User.CurrentUser = ""
+	}
 	return message, nil
 }
 
-func fn_fdisk(tokens []string) (string, error) {
-	// Definir flags
-	fs := flag.NewFlagSet("fdisk", flag.ExitOnError)
-	size := fs.Int("size", 0, "Tamaño")
-	path := fs.String("path", "", "Ruta")
-	name := fs.String("name", "", "Nombre")
-	unit := fs.String("unit", "m", "Unidad")
-	type_ := fs.String("type", "p", "Tipo")
-	fit := fs.String("fit", "", "Ajuste") // Dejar fit vacío por defecto
-
-	// Parsear los flags
-	fs.Parse(os.Args[1:])
-
-	// Encontrar los flags en el input
-	matches := re.FindAllStringSubmatch(strings.Join(tokens, " "), -1)
-
-	// Procesar el input
-	for _, match := range matches {
-		flagName := match[1]
-		flagValue := strings.ToLower(match[2])
-
-		flagValue = strings.Trim(flagValue, "\"")
-
-		switch flagName {
-		case "size", "fit", "unit", "path", "name", "type":
-			fs.Set(flagName, flagValue)
-		default:
-			fmt.Println("Error: Flag not found")
-		}
+// rmdiskConfirm exige -confirm: el frontend no puede preguntar, así que la confirmación va en la misma línea
+func rmdiskConfirm(cmd Structs.Command) error {
+	if !cmd.Has("confirm") {
+		return fmt.Errorf("rmdisk mueve el disco %s a la papelera; agregue -confirm para continuar", cmd.Value("path"))
 	}
+	return nil
+}
 
-	// Validaciones
-	if *size <= 0 {
-		fmt.Println("Error: Size must be greater than 0")
-		return ``, fmt.Errorf("parámetro inválido: %v", *size)
-	}
+func fn_restoredisk(cmd Structs.Command) (string, error) {
+	return DiskManagement.RestoreDisk(cmd.Value("path"), cmd.Value("entry"))
+}
 
-	if *path == "" {
-		fmt.Println("Error: Path is required")
-		return ``, fmt.Errorf("parámetro inválido: %s", *path)
+// fdiskMode revisa la combinación de parámetros de fdisk antes de crear, redimensionar o eliminar
+func fdiskMode(cmd Structs.Command) error {
+	if cmd.Has("add") {
+		if cmd.Has("size") || cmd.Has("delete") {
+			return errors.New("-add no se puede combinar con -size ni con -delete")
+		}
+		if cmd.Int("add") == 0 {
+			return errors.New("-add debe ser distinto de cero")
+		}
+		return nil
 	}
-
-	// Si no se proporcionó un fit, usar el valor predeterminado "w"
-	if *fit == "" {
-		*fit = "w"
+	if cmd.Has("delete") {
+		if cmd.Has("size") {
+			return errors.New("-delete no se puede combinar con -size")
+		}
+		// El frontend no puede preguntar, así que la confirmación va en la misma línea
+		if !cmd.Has("confirm") {
+			return fmt.Errorf("eliminar la partición %s no se puede deshacer; agregue -confirm para continuar", cmd.Value("name"))
+		}
+		return nil
 	}
-
-	// Validar fit (b/w/f)
-	if *fit != "b" && *fit != "f" && *fit != "w" {
-		fmt.Println("Error: Fit must be 'b', 'f', or 'w'")
-		return ``, fmt.Errorf("parámetro inválido: %s", *fit)
+	if !cmd.Has("size") {
+		return errors.New("falta el parámetro obligatorio -size")
 	}
+	return nil
+}
 
-	if *unit != "k" && *unit != "m" {
-		fmt.Println("Error: Unit must be 'k' or 'm'")
-		return ``, fmt.Errorf("parámetro inválido: %s", *unit)
+func fn_fdisk(cmd Structs.Command) (string, error) {
+	if err := fdiskMode(cmd); err != nil {
+		return "", err
 	}
-
-	if *type_ != "p" && *type_ != "e" && *type_ != "l" {
-		fmt.Println("Error: Type must be 'p', 'e', or 'l'")
-		return ``, fmt.Errorf("parámetro inválido: %s", *type_)
+	if cmd.Has("delete") {
+		return DiskManagement.DeletePartition(cmd.Value("path"), cmd.Value("name"), cmd.Value("delete"))
+	}
+	if cmd.Has("add") {
+		return DiskManagement.ResizePartition(cmd.Value("path"), cmd.Value("name"), sizeInBytes(cmd.Int("add"), cmd.Value("unit")))
 	}
 
 	// Llamar a la función
-	message, err := DiskManagement.Fdisk(*size, *path, *name, *unit, *type_, *fit)
+	message, err := DiskManagement.Fdisk(cmd.Int("size"), cmd.Value("path"), cmd.Value("name"), cmd.Value("unit"), cmd.Value("type"), cmd.Value("fit"))
 	if err != nil {
 		return "", err
 	}
 	return message, nil
 }
 
-func fn_mount(tokens []string) (string, error) {
-	fs := flag.NewFlagSet("mount", flag.ExitOnError)
-	path := fs.String("path", "", "Ruta")
-	name := fs.String("name", "", "Nombre de la partición")
-
-	// Parsear los argumentos
-	fs.Parse(os.Args[1:])
-	matches := re.FindAllStringSubmatch(strings.Join(tokens, " "), -1)
-
-	// Procesar los parámetros del comando
-	for _, match := range matches {
-		flagName := match[1]
-		flagValue := strings.ToLower(match[2]) // Convertir todo a minúsculas
-		flagValue = strings.Trim(flagValue, "\"")
-		fs.Set(flagName, flagValue)
-	}
-
-	// Validar los parámetros requeridos
-	if *path == "" || *name == "" {
-		fmt.Println("Error: Path y Name son obligatorios")
-		return "", fmt.Errorf("parámetro inválido: %s", *path)
-	}
-
-	// Convertir el nombre a minúsculas antes de pasarlo al Mount
-	lowercaseName := strings.ToLower(*name)
-
+func fn_mount(cmd Structs.Command) (string, error) {
 	// Llamar a la función de montaje y capturar su retorno
-	message, err := DiskManagement.Mount(*path, lowercaseName)
+	message, err := DiskManagement.Mount(cmd.Value("path"), cmd.Value("name"))
 	if err != nil {
 		return message, err
 	}
@@ -281,206 +199,140 @@ func fn_mount(tokens []string) (string, error) {
 	return message, nil
 }
 
-func fn_mkfs(tokens []string) (string, error) {
-	fs := flag.NewFlagSet("mkfs", flag.ExitOnError)
-	id := fs.String("id", "", "Id")
-	type_ := fs.String("type", "", "Tipo")
-	fs_ := fs.String("fs", "2fs", "Fs")
-
-	// Parse the input string, not os.Args
-	matches := re.FindAllStringSubmatch(strings.Join(tokens, " "), -1)
-
-	for _, match := range matches {
-		flagName := match[1]
-		flagValue := match[2]
-
-		flagValue = strings.Trim(flagValue, "\"")
-
-		switch flagName {
-		case "id", "type", "fs":
-			fs.Set(flagName, flagValue)
-		default:
-			fmt.Println("Error: Flag not found")
-		}
-	}
-
-	// Verifica que se hayan establecido todas las flags necesarias
-	if *id == "" {
-		fmt.Println("Error: id es un parámetro obligatorio.")
-		return "", fmt.Errorf("parámetro inválido: %s", *id)
+func fn_unmount(cmd Structs.Command) (string, error) {
+	id := cmd.Value("id")
+	message, err := DiskManagement.Unmount(id)
+	if err != nil {
+		return message, err
 	}
 
-	if *type_ == "" {
-		fmt.Println("Error: type es un parámetro obligatorio.")
-		return "", fmt.Errorf("parámetro inválido: %s", *type_)
+	// La sesión de la partición desmontada se cierra
+	if DiskManagement.SameID(User.CurrentLoggedPartitionID, id) {
+		User.CurrentLoggedPartitionID = ""
+		User.CurrentUser = ""
 	}
+	return message, nil
+}
 
+func fn_mkfs(cmd Structs.Command) (string, error) {
 	// Llamar a la función
-	message, err := FileSystem.Mkfs(*id, *type_, *fs_)
+	message, err := FileSystem.Mkfs(cmd.Value("id"), cmd.Value("type"), cmd.Value("fs"))
 	if err != nil {
 		return "", err
 	}
 	return message, nil
 }
 
-func fn_login(tokens []string) (string, error) {
-	// Definir las flags
-	fs := flag.NewFlagSet("login", flag.ExitOnError)
-	user := fs.String("user", "", "Usuario")
-	pass := fs.String("pass", "", "Contraseña")
-	id := fs.String("id", "", "Id")
-
-	// Parsearlas
-	fs.Parse(os.Args[1:])
-
-	// Match de flags en el input
-	matches := re.FindAllStringSubmatch(strings.Join(tokens, " "), -1)
-
-	// Procesar el input
-	for _, match := range matches {
-		flagName := match[1]
-		flagValue := match[2]
-
-		flagValue = strings.Trim(flagValue, "\"")
-
-		switch flagName {
-		case "user", "pass", "id":
-			fs.Set(flagName, flagValue)
-		default:
-			fmt.Println("Error: Flag not found")
-		}
-	}
-
-	message, err := User.Login(*user, *pass, *id)
+func fn_login(cmd Structs.Command) (string, error) {
+	message, err := User.Login(cmd.Value("user"), cmd.Value("pass"), cmd.Value("id"))
 	if err != nil {
 		return "", err
 	}
 	return message, nil
 }
 
-func fn_rep(tokens []string) (string, error) {
-	// Definir flags para el comando rep
-	fs := flag.NewFlagSet("rep", flag.ExitOnError)
-	name := fs.String("name", "", "Nombre del reporte a generar (mbr, disk, inode, block, bm_inode, bm_block, sb, file, ls)")
-	path := fs.String("path", "", "Ruta donde se guardará el reporte")
-	id := fs.String("id", "", "ID de la partición que se utilizará")
-	pathFileLs := fs.String("path_file_ls", "", "Nombre del archivo o carpeta para los reportes 'file' y 'ls'")
-
-	// Parsear los flags
-	fs.Parse(os.Args[1:])
-	// Match de flags en el input
-	matches := re.FindAllStringSubmatch(strings.Join(tokens, " "), -1)
-
-	// Procesar los parámetros del input
-	for _, match := range matches {
-		flagName := match[1]
-		flagValue := match[2]
-		flagValue = strings.Trim(flagValue, "\"")
-
-		switch flagName {
-		case "name", "path", "id", "path_file_ls":
-			fs.Set(flagName, flagValue)
-		default:
-			fmt.Println("Error: Flag not found")
-			return "", fmt.Errorf("parámetro inválido: %s", flagName)
-		}
-	}
-
-	// Validar los parámetros obligatorios
-	if *name == "" {
-		fmt.Println("Error: El parámetro -name es obligatorio y debe contener un valor válido (mbr, disk, inode, block, bm_inode, bm_block, sb, file, ls)")
-		return "", fmt.Errorf("parámetro inválido: %s", *name)
-	}
-
-	if *path == "" {
-		fmt.Println("Error: El parámetro -path es obligatorio.")
-		return "", fmt.Errorf("parámetro inválido: %s", *path)
-	}
+func fn_logout(cmd Structs.Command) (string, error) {
+	return User.Logout()
+}
 
-	if *id == "" {
-		fmt.Println("Error: El parámetro -id es obligatorio.")
-		return "", fmt.Errorf("parámetro inválido: %s", *id)
-	}
+func fn_rep(cmd Structs.Command) (string, error) {
+	name := cmd.Value("name")
+	path := cmd.Value("path")
+	id := cmd.Value("id")
+	pathFileLs := cmd.Value("path_file_ls")
 
-	// Verificar que el nombre del reporte es válido
-	validReports := []string{"mbr", "disk", "inode", "block", "bm_inode", "bm_block", "sb", "file", "ls"}
-	if !isValidReportName(*name, validReports) {
-		fmt.Println("Error: Nombre de reporte no válido.")
-		return "", fmt.Errorf("parámetro inválido: %s", *name)
+	// Los reportes file y ls necesitan saber qué archivo o carpeta mostrar
+	if (name == "file" || name == "ls") && pathFileLs == "" {
+		fmt.Printf("Error: El parámetro -path_file_ls es obligatorio para el reporte %s.\n", name)
+		return "", fmt.Errorf("falta el parámetro -path_file_ls para el reporte %s", name)
 	}
 
 	// Verificar que la partición con el id existe
-	partition := DiskManagement.GetPartitionByID(*id)
+	partition := DiskManagement.GetPartitionByID(id)
 	if partition == nil {
 		fmt.Println("Error: No se encontró la partición con el id proporcionado.")
-		return "", fmt.Errorf("partición no encontrada: %s", *id)
+		return "", fmt.Errorf("partición no encontrada: %s", id)
 	}
 
 	// Generar el reporte con Graphviz
-	switch *name {
+	var err error
+	switch name {
 	case "mbr":
-		DiskManagement.GenerateMBRReport(*path, *partition)
+		err = DiskManagement.GenerateMBRReport(path, *partition)
 	case "disk":
-		DiskManagement.GenerateDiskReport(*path, partition)
+		err = DiskManagement.GenerateDiskReport(path, partition)
 	case "inode":
-		DiskManagement.GenerateInodeReport(*path, partition)
+		err = DiskManagement.GenerateInodeReport(path, partition)
 	case "block":
-		DiskManagement.GenerateBlockReport(*path, partition)
-	case "bm_inode":
-		DiskManagement.GenerateBMInodeReport(*path, *partition)
-	case "bm_block":
-		DiskManagement.GenerateBMBlockReport(*path, *partition)
+		err = DiskManagement.GenerateBlockReport(path, partition)
 	case "sb":
-		DiskManagement.GenerateSuperblockReport(*path, partition)
-	case "file":
-		if *pathFileLs == "" {
-			fmt.Println("Error: El parámetro -path_file_ls es obligatorio para el reporte file.")
-			return "", fmt.Errorf("parámetro inválido: %s", *pathFileLs)
-		}
-		DiskManagement.GenerateFileReport(*path, *partition, *pathFileLs)
-	case "ls":
-		if *pathFileLs == "" {
-			fmt.Println("Error: El parámetro -path_file_ls es obligatorio para el reporte ls.")
-			return "", fmt.Errorf("parámetro inválido: %s", *pathFileLs)
-		}
-		DiskManagement.GenerateLsReport(*path, *partition, *pathFileLs)
+		err = DiskManagement.GenerateSuperblockReport(path, partition)
 	default:
-		fmt.Println("Error: Nombre de reporte no válido.")
+		// bm_inode, bm_block, file y ls todavía no generan ningún archivo
+		return "", fmt.Errorf("el reporte %s aún no está implementado", name)
 	}
-	return "REP: Reporte " + *name + " exitosamente en: " + *path, nil
+	if err != nil {
+		return "", err
+	}
+	return "REP: Reporte " + name + " exitosamente en: " + path, nil
 }
 
-// Verifica si el nombre del reporte es válido
-func isValidReportName(name string, validReports []string) bool {
-	for _, report := range validReports {
-		if name == report {
-			return true
-		}
-	}
-	return false
+// repArtifacts devuelve la imagen que genera el comando rep
+func repArtifacts(cmd Structs.Command) []string {
+	return []string{DiskManagement.ReportImagePath(cmd.Value("path"))}
 }
 
-func fn_mkdir(tokens []string) (string, error) {
-	// Parsear los argumentos del comando mkdir
-	args := make(map[string]string)
-	for _, token := range tokens {
-		matches := re.FindStringSubmatch(token)
-		if len(matches) == 3 {
-			args[matches[1]] = strings.Trim(matches[2], `"`)
-		}
-	}
+// diskArtifacts devuelve el disco que crean los comandos mkdisk e importmbr
+func diskArtifacts(cmd Structs.Command) []string {
+	return []string{cmd.Value("path")}
+}
 
-	// Verificar que se haya proporcionado el argumento -path
-	path, ok := args["path"]
-	if !ok {
-		return "", errors.New("no se proporcionó el argumento -path")
-	}
+// imageArtifacts devuelve la imagen que genera el comando exportmbr
+func imageArtifacts(cmd Structs.Command) []string {
+	return []string{cmd.Value("image")}
+}
 
+func fn_mkdir(cmd Structs.Command) (string, error) {
 	// Llamar a la función Mkdir para crear los directorios
-	logs, err := FileSystem.Mkdir(path)
+	logs, err := FileSystem.Mkdir(cmd.Value("path"))
 	if err != nil {
 		return logs, err
 	}
 
 	return logs, nil
 }
+
+func fn_mkfile(cmd Structs.Command) (string, error) {
+	return FileSystem.ParserMkfile(cmd)
+}
+
+func fn_cat(cmd Structs.Command) (string, error) {
+	// Los parámetros -file1, -file2, ... se pasan en el orden de la línea
+	var files []string
+	for _, param := range cmd.Params {
+		files = append(files, param.Value)
+	}
+	return FileSystem.Cat(files)
+}
+
+// ClearScreen, si no es nil, reemplaza la forma en que clear limpia la pantalla (por ejemplo, en el modo interactivo)
+var ClearScreen func() error
+
+func fn_clear(cmd Structs.Command) (string, error) {
+	if ClearScreen != nil {
+		if err := ClearScreen(); err != nil {
+			return "", errors.New("no se pudo limpiar la terminal")
+		}
+		return "", nil
+	}
+
+	// Crea un comando para limpiar la terminal
+	clear := exec.Command("clear")
+	clear.Stdout = os.Stdout // Redirige la salida del comando a la salida estándar
+	err := clear.Run()       // Ejecuta el comando
+	if err != nil {
+		// Si hay un error al ejecutar el comando, devuelve un error
+		return "", errors.New("no se pudo limpiar la terminal")
+	}
+	return "", nil // Devuelve nil si el comando se ejecutó correctamente
+}
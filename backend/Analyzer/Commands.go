@@ -0,0 +1,531 @@
+package Analyzer
+
+import (
+	"backend/Structs"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Tipos de valor que puede recibir un parámetro
+const (
+	TypeString = "texto"
+	TypeInt    = "entero"
+	TypeFlag   = "bandera" // Parámetro sin valor, como -r
+)
+
+// Reglas de mayúsculas/minúsculas que se aplican al valor antes de validarlo
+const (
+	CaseKeep  = iota // El valor se conserva tal cual
+	CaseLower        // El valor se convierte a minúsculas
+)
+
+// ParamSpec describe un parámetro que acepta un comando
+type ParamSpec struct {
+	Name     string   // Nombre sin guion
+	Required bool     // true si el comando no puede ejecutarse sin él
+	Type     string   // TypeString, TypeInt o TypeFlag
+	Allowed  []string // Valores permitidos (ya en el caso indicado por Case); vacío acepta cualquiera
+	Default  string   // Valor que se usa si el parámetro no aparece
+	Case     int      // CaseKeep o CaseLower
+	Min      int      // Valor mínimo para TypeInt
+	Numbered bool     // Acepta el nombre seguido de un número (-file1, -file2, ...)
+	HostPath bool     // El valor es una ruta del host que replay redirige a su carpeta de discos nuevos
+	Help     string   // Descripción para el comando help
+}
+
+// CommandSpec describe un comando: sus parámetros y la función que lo ejecuta
+type CommandSpec struct {
+	Name   string
+	Help   string
+	Arg    string // Nombre del argumento posicional opcional; vacío si no acepta ninguno
+	Params []ParamSpec
+	Run    func(cmd Structs.Command) (string, error)
+
+	// Artifacts devuelve los archivos que genera el comando al terminar bien (discos, reportes); puede ser nil
+	Artifacts func(cmd Structs.Command) []string
+
+	// DiskPath indica que -path es la ruta del disco; se usa para registrar el disco en el historial
+	DiskPath bool
+
+	// NoJournal excluye el comando del historial (no modifica discos ni sesión, o sus líneas se registran por separado)
+	NoJournal bool
+
+	// DryRun simula el comando sobre el modelo en memoria sin tocar los discos; nil en execute, que simula línea por línea su script
+	DryRun func(state *dryRunState, cmd Structs.Command) (string, error)
+}
+
+// Registro de todos los comandos, en el orden en que se muestran en help
+var commands []CommandSpec
+
+func init() {
+	commands = []CommandSpec{
+		{
+			Name: "mkdisk",
+			Help: "Crea un disco virtual (.mia) con su MBR o su GPT",
+			Params: []ParamSpec{
+				{Name: "size", Required: true, Type: TypeInt, Min: 1, Help: "Tamaño del disco"},
+				{Name: "path", Required: true, Type: TypeString, Case: CaseLower, Help: "Ruta del archivo del disco", HostPath: true},
+				{Name: "fit", Type: TypeString, Allowed: []string{"bf", "ff", "wf"}, Default: "ff", Case: CaseLower, Help: "Ajuste del disco"},
+				{Name: "unit", Type: TypeString, Allowed: []string{"k", "m"}, Default: "m", Case: CaseLower, Help: "Unidad del tamaño"},
+				{Name: "zero", Type: TypeString, Allowed: []string{"full", "none"}, Default: "full", Case: CaseLower, Help: "Llenado del disco: full escribe los ceros, none crea un archivo disperso"},
+				{Name: "format", Type: TypeString, Allowed: []string{"32", "64"}, Default: "32", Help: "Formato del disco: 32 bits (el original) o 64 bits para discos de más de 2 GiB"},
+				{Name: "table", Type: TypeString, Allowed: []string{"mbr", "gpt"}, Default: "mbr", Case: CaseLower, Help: "Tabla de particiones: mbr (4 slots y lógicas) o gpt (solo primarias, siempre de 64 bits)"},
+				{Name: "entries", Type: TypeInt, Default: "128", Min: 1, Help: "Cantidad de entradas de la GPT, es decir, de particiones que admite"},
+			},
+			DiskPath:  true,
+			Run:       fn_mkdisk,
+			DryRun:    dry_mkdisk,
+			Artifacts: diskArtifacts,
+		},
+		{
+			Name: "rmdisk",
+			Help: "Mueve un disco virtual a la papelera; restoredisk lo recupera",
+			Params: []ParamSpec{
+				{Name: "path", Required: true, Type: TypeString, Help: "Ruta del archivo del disco", HostPath: true},
+				{Name: "confirm", Type: TypeFlag, Help: "Confirma la eliminación; sin él rmdisk no hace nada"},
+				{Name: "force", Type: TypeFlag, Help: "Desmonta las particiones del disco, y cierra su sesión, antes de eliminarlo"},
+			},
+			DiskPath: true,
+			Run:      fn_rmdisk,
+			DryRun:   dry_rmdisk,
+		},
+		{
+			Name: "restoredisk",
+			Help: "Devuelve a su ruta un disco que está en la papelera",
+			Params: []ParamSpec{
+				{Name: "path", Required: true, Type: TypeString, Case: CaseLower, Help: "Ruta original del disco", HostPath: true},
+				{Name: "entry", Type: TypeString, Help: "Entrada de la papelera que se restaura; por defecto la eliminada más recientemente"},
+			},
+			DiskPath:  true,
+			Run:       fn_restoredisk,
+			DryRun:    dry_restoredisk,
+			Artifacts: diskArtifacts,
+		},
+		{
+			Name: "convertdisk",
+			Help: "Convierte un disco de 32 bits al formato de 64 bits, en su lugar",
+			Params: []ParamSpec{
+				{Name: "path", Required: true, Type: TypeString, Case: CaseLower, Help: "Ruta del archivo del disco", HostPath: true},
+			},
+			DiskPath: true,
+			Run:      fn_convertdisk,
+			DryRun:   dry_convertdisk,
+		},
+		{
+			Name: "exportmbr",
+			Help: "Exporta el disco a una imagen con un MBR de DOS que leen fdisk, sfdisk y parted",
+			Params: []ParamSpec{
+				{Name: "path", Required: true, Type: TypeString, Case: CaseLower, Help: "Ruta del archivo del disco", HostPath: true},
+				{Name: "image", Required: true, Type: TypeString, Help: "Ruta de la imagen que se crea", HostPath: true},
+			},
+			DiskPath:  true,
+			Run:       fn_exportmbr,
+			DryRun:    dry_exportmbr,
+			Artifacts: imageArtifacts,
+		},
+		{
+			Name: "importmbr",
+			Help: "Crea un disco a partir de una imagen con un MBR de DOS",
+			Params: []ParamSpec{
+				{Name: "image", Required: true, Type: TypeString, Help: "Ruta de la imagen", HostPath: true},
+				{Name: "path", Required: true, Type: TypeString, Case: CaseLower, Help: "Ruta del archivo del disco que se crea", HostPath: true},
+			},
+			DiskPath:  true,
+			Run:       fn_importmbr,
+			DryRun:    dry_importmbr,
+			Artifacts: diskArtifacts,
+		},
+		{
+			Name: "fdisk",
+			Help: "Crea, redimensiona o elimina una partición primaria, extendida o lógica",
+			Params: []ParamSpec{
+				{Name: "size", Type: TypeInt, Min: 1, Help: "Tamaño de la partición; obligatorio al crear"},
+				{Name: "path", Required: true, Type: TypeString, Case: CaseLower, Help: "Ruta del archivo del disco", HostPath: true},
+				{Name: "name", Required: true, Type: TypeString, Case: CaseLower, Help: "Nombre de la partición"},
+				{Name: "unit", Type: TypeString, Allowed: []string{"b", "k", "m"}, Default: "m", Case: CaseLower, Help: "Unidad del tamaño"},
+				{Name: "type", Type: TypeString, Allowed: []string{"p", "e", "l"}, Default: "p", Case: CaseLower, Help: "Tipo de partición"},
+				{Name: "fit", Type: TypeString, Allowed: []string{"b", "f", "w"}, Default: "w", Case: CaseLower, Help: "Ajuste de la partición"},
+				{Name: "add", Type: TypeInt, Min: math.MinInt, Help: "Agrega (positivo) o quita (negativo) espacio a la partición, en la unidad de -unit"},
+				{Name: "delete", Type: TypeString, Allowed: []string{"fast", "full"}, Case: CaseLower, Help: "Elimina la partición: fast libera su lugar, full además la llena con ceros"},
+				{Name: "confirm", Type: TypeFlag, Help: "Confirma la eliminación con -delete"},
+			},
+			DiskPath: true,
+			Run:      fn_fdisk,
+			DryRun:   dry_fdisk,
+		},
+		{
+			Name: "mount",
+			Help: "Monta una partición y le asigna un ID",
+			Params: []ParamSpec{
+				{Name: "path", Required: true, Type: TypeString, Case: CaseLower, Help: "Ruta del archivo del disco", HostPath: true},
+				{Name: "name", Required: true, Type: TypeString, Case: CaseLower, Help: "Nombre de la partición"},
+			},
+			DiskPath: true,
+			Run:      fn_mount,
+			DryRun:   dry_mount,
+		},
+		{
+			Name: "unmount",
+			Help: "Desmonta una partición y cierra su sesión si la tiene",
+			Params: []ParamSpec{
+				{Name: "id", Required: true, Type: TypeString, Help: "ID de la partición montada"},
+			},
+			Run:    fn_unmount,
+			DryRun: dry_unmount,
+		},
+		{
+			Name: "mounted",
+			Help: "Lista las particiones montadas con su disco, tipo, tamaño, sistema de archivos y sesión",
+			Params: []ParamSpec{
+				{Name: "json", Type: TypeFlag, Help: "Devuelve la lista en JSON"},
+			},
+			NoJournal: true,
+			Run:       fn_mounted,
+			DryRun:    dry_mounted,
+		},
+		{
+			Name: "lsdisk",
+			Help: "Lista los discos .mia de una carpeta del host con su MBR y su tabla de particiones",
+			Params: []ParamSpec{
+				{Name: "dir", Required: true, Type: TypeString, Case: CaseLower, Help: "Carpeta donde buscar (incluye subcarpetas)"},
+				{Name: "json", Type: TypeFlag, Help: "Devuelve la lista en JSON"},
+			},
+			NoJournal: true,
+			Run:       fn_lsdisk,
+			DryRun:    dry_lsdisk,
+		},
+		{
+			Name: "mkfs",
+			Help: "Formatea una partición montada con EXT2",
+			Params: []ParamSpec{
+				{Name: "id", Required: true, Type: TypeString, Help: "ID de la partición montada"},
+				{Name: "type", Required: true, Type: TypeString, Allowed: []string{"full"}, Case: CaseLower, Help: "Tipo de formateo"},
+				{Name: "fs", Type: TypeString, Allowed: []string{"2fs"}, Default: "2fs", Case: CaseLower, Help: "Sistema de archivos"},
+			},
+			Run:    fn_mkfs,
+			DryRun: dry_mkfs,
+		},
+		{
+			Name: "login",
+			Help: "Inicia sesión en una partición montada",
+			Params: []ParamSpec{
+				{Name: "user", Required: true, Type: TypeString, Help: "Nombre del usuario"},
+				{Name: "pass", Required: true, Type: TypeString, Help: "Contraseña"},
+				{Name: "id", Required: true, Type: TypeString, Help: "ID de la partición montada"},
+			},
+			Run:    fn_login,
+			DryRun: dry_login,
+		},
+		{
+			Name:   "logout",
+			Help:   "Cierra la sesión activa",
+			Run:    fn_logout,
+			DryRun: dry_logout,
+		},
+		{
+			Name: "mkdir",
+			Help: "Crea una carpeta en la partición con sesión activa",
+			Params: []ParamSpec{
+				{Name: "path", Required: true, Type: TypeString, Help: "Ruta de la carpeta"},
+			},
+			Run:    fn_mkdir,
+			DryRun: dry_mkdir,
+		},
+		{
+			Name: "mkfile",
+			Help: "Crea un archivo en la partición con sesión activa",
+			Params: []ParamSpec{
+				{Name: "path", Required: true, Type: TypeString, Help: "Ruta del archivo"},
+				{Name: "r", Type: TypeFlag, Help: "Crea las carpetas padre si no existen"},
+				{Name: "size", Type: TypeInt, Min: 0, Help: "Tamaño del archivo en bytes"},
+				{Name: "cont", Type: TypeString, Help: "Contenido del archivo"},
+			},
+			Run:    fn_mkfile,
+			DryRun: dry_mkfile,
+		},
+		{
+			Name: "cat",
+			Help: "Muestra el contenido de uno o varios archivos",
+			Params: []ParamSpec{
+				{Name: "file", Required: true, Type: TypeString, Numbered: true, Help: "Ruta del archivo (-file1, -file2, ...)"},
+			},
+			Run:    fn_cat,
+			DryRun: dry_cat,
+		},
+		{
+			Name: "rep",
+			Help: "Genera un reporte de una partición montada",
+			Params: []ParamSpec{
+				{Name: "name", Required: true, Type: TypeString, Allowed: validReports, Case: CaseLower, Help: "Nombre del reporte"},
+				{Name: "path", Required: true, Type: TypeString, Help: "Ruta donde se guardará el reporte", HostPath: true},
+				{Name: "id", Required: true, Type: TypeString, Help: "ID de la partición montada"},
+				{Name: "path_file_ls", Type: TypeString, Help: "Archivo o carpeta para los reportes file y ls"},
+			},
+			Run:       fn_rep,
+			DryRun:    dry_rep,
+			Artifacts: repArtifacts,
+		},
+		{
+			Name: "execute",
+			Help: "Ejecuta un script (.smia, .txt) del host línea por línea",
+			Params: []ParamSpec{
+				{Name: "path", Required: true, Type: TypeString, Help: "Ruta del script en el host"},
+				{Name: "onerror", Type: TypeString, Allowed: []string{"stop", "continue"}, Case: CaseLower, Help: "Detenerse en el primer error o continuar"},
+				{Name: "atomic", Type: TypeFlag, Help: "Si alguna línea falla, deshace los cambios del script en los discos, montajes y sesión"},
+				{Name: "dryrun", Type: TypeFlag, Help: "Valida y simula el script sin crear ni modificar discos"},
+			},
+			NoJournal: true,
+			Run:       fn_execute,
+		},
+		{
+			Name: "history",
+			Help: "Muestra el historial de comandos ejecutados",
+			Params: []ParamSpec{
+				{Name: "disk", Type: TypeString, Case: CaseLower, Help: "Solo comandos sobre este disco"},
+				{Name: "user", Type: TypeString, Help: "Solo comandos de este usuario"},
+				{Name: "from", Type: TypeString, Help: "Desde este número de entrada o fecha (AAAA-MM-DD [HH:MM[:SS]])"},
+				{Name: "to", Type: TypeString, Help: "Hasta este número de entrada o fecha (AAAA-MM-DD [HH:MM[:SS]])"},
+			},
+			NoJournal: true,
+			Run:       fn_history,
+			DryRun:    dry_history,
+		},
+		{
+			Name: "replay",
+			Help: "Vuelve a ejecutar un tramo del historial sobre discos nuevos",
+			Params: []ParamSpec{
+				{Name: "from", Required: true, Type: TypeString, Help: "Desde este número de entrada o fecha"},
+				{Name: "to", Type: TypeString, Help: "Hasta este número de entrada o fecha; por defecto hasta el final"},
+				{Name: "dir", Type: TypeString, Help: "Carpeta donde se crean los discos; por defecto una nueva dentro de la carpeta de datos"},
+			},
+			NoJournal: true,
+			Run:       fn_replay,
+			DryRun:    dry_replay,
+		},
+		{
+			Name:      "clear",
+			Help:      "Limpia la terminal",
+			NoJournal: true,
+			Run:       fn_clear,
+			DryRun:    dry_clear,
+		},
+		{
+			Name:      "help",
+			Help:      "Muestra los comandos disponibles o la sintaxis de uno",
+			Arg:       "comando",
+			NoJournal: true,
+			Run:       fn_help,
+			DryRun:    dry_help,
+		},
+	}
+}
+
+// findCommand busca un comando en el registro por su nombre
+func findCommand(name string) *CommandSpec {
+	for i := range commands {
+		if commands[i].Name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+// findParam busca la definición de un parámetro dentro de un comando
+func (spec *CommandSpec) findParam(name string) *ParamSpec {
+	for i := range spec.Params {
+		param := &spec.Params[i]
+		if param.Name == name {
+			return param
+		}
+		if param.Numbered && strings.HasPrefix(name, param.Name) {
+			if _, err := strconv.Atoi(name[len(param.Name):]); err == nil {
+				return param
+			}
+		}
+	}
+	return nil
+}
+
+// validate revisa el comando contra su definición y reporta todos los problemas juntos.
+// Devuelve el comando normalizado: valores con su regla de mayúsculas y valores por defecto.
+// En modo no estricto los parámetros y valores desconocidos se ignoran y se devuelven como advertencias.
+func validate(spec *CommandSpec, cmd Structs.Command, opts Options) (Structs.Command, []string, error) {
+	var problems, warnings []string
+	normalized := cmd
+	normalized.Params = nil
+	seen := make(map[string]bool)
+
+	// Argumentos posicionales
+	if spec.Arg == "" {
+		for _, arg := range cmd.Args {
+			msg := fmt.Sprintf("valor inesperado '%s' en la columna %d: los parámetros deben tener la forma -nombre=valor", arg.Value, arg.Column)
+			if opts.Strict {
+				problems = append(problems, msg)
+			} else {
+				warnings = append(warnings, msg+" (ignorado)")
+			}
+		}
+	} else if len(cmd.Args) > 1 {
+		problems = append(problems, fmt.Sprintf("solo se acepta un %s, se encontraron %d", spec.Arg, len(cmd.Args)))
+	}
+
+	// Parámetros presentes en la línea
+	for _, param := range cmd.Params {
+		def := spec.findParam(param.Name)
+		if def == nil {
+			msg := fmt.Sprintf("parámetro desconocido -%s en la columna %d%s", param.Name, param.Column, didYouMean(param.Name, spec.paramNames(), "-"))
+			if opts.Strict {
+				problems = append(problems, msg)
+			} else {
+				warnings = append(warnings, msg+" (ignorado)")
+			}
+			continue
+		}
+		if seen[param.Name] {
+			problems = append(problems, fmt.Sprintf("parámetro -%s repetido en la columna %d", param.Name, param.Column))
+			continue
+		}
+		seen[param.Name] = true
+		seen[def.Name] = true
+
+		if def.Type == TypeFlag {
+			if param.HasValue {
+				problems = append(problems, fmt.Sprintf("-%s no recibe valor", param.Name))
+			}
+			normalized.Params = append(normalized.Params, param)
+			continue
+		}
+
+		if !param.HasValue || param.Value == "" {
+			problems = append(problems, fmt.Sprintf("-%s requiere un valor (%s)", param.Name, def.Type))
+			continue
+		}
+
+		if def.Case == CaseLower {
+			param.Value = strings.ToLower(param.Value)
+		}
+
+		if def.Type == TypeInt {
+			n, err := strconv.Atoi(param.Value)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("-%s debe ser un número entero, se recibió '%s'", param.Name, param.Value))
+				continue
+			}
+			if n < def.Min {
+				problems = append(problems, fmt.Sprintf("-%s debe ser mayor o igual a %d, se recibió %d", param.Name, def.Min, n))
+				continue
+			}
+		}
+
+		if len(def.Allowed) > 0 && !contains(def.Allowed, param.Value) {
+			problems = append(problems, fmt.Sprintf("valor inválido para -%s: '%s' (permitidos: %s)", param.Name, param.Value, strings.Join(def.Allowed, ", ")))
+			continue
+		}
+
+		normalized.Params = append(normalized.Params, param)
+	}
+
+	// Parámetros obligatorios y valores por defecto
+	for _, def := range spec.Params {
+		if seen[def.Name] {
+			continue
+		}
+		if def.Required {
+			problems = append(problems, fmt.Sprintf("falta el parámetro obligatorio -%s", helpParamName(def)))
+			continue
+		}
+		if def.Default != "" {
+			normalized.Params = append(normalized.Params, Structs.Parameter{Name: def.Name, Value: def.Default, HasValue: true})
+		}
+	}
+
+	if len(problems) > 0 {
+		// Las advertencias suelen explicar el error (por ejemplo -szie ignorado y falta -size)
+		problems = append(warnings, problems...)
+		return normalized, nil, fmt.Errorf("%s: %d error(es) en los parámetros:\n - %s", spec.Name, len(problems), strings.Join(problems, "\n - "))
+	}
+	return normalized, warnings, nil
+}
+
+// contains indica si un valor está en la lista
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// helpParamName devuelve el nombre como se muestra al usuario (-file1, -file2, ... para los numerados)
+func helpParamName(def ParamSpec) string {
+	if def.Numbered {
+		return def.Name + "N"
+	}
+	return def.Name
+}
+
+// Usage arma la línea de sintaxis de un comando a partir de su definición
+func (spec *CommandSpec) Usage() string {
+	var usage strings.Builder
+	usage.WriteString(spec.Name)
+	if spec.Arg != "" {
+		usage.WriteString(fmt.Sprintf(" [%s]", spec.Arg))
+	}
+	for _, def := range spec.Params {
+		var part string
+		switch {
+		case def.Type == TypeFlag:
+			part = "-" + def.Name
+		case len(def.Allowed) > 0:
+			part = fmt.Sprintf("-%s=%s", helpParamName(def), strings.Join(def.Allowed, "|"))
+		default:
+			part = fmt.Sprintf("-%s=<%s>", helpParamName(def), def.Type)
+		}
+		if !def.Required {
+			part = "[" + part + "]"
+		}
+		usage.WriteString(" " + part)
+	}
+	return usage.String()
+}
+
+// fn_help muestra la lista de comandos o la sintaxis detallada de uno
+func fn_help(cmd Structs.Command) (string, error) {
+	if len(cmd.Args) == 0 {
+		var logs strings.Builder
+		logs.WriteString("Comandos disponibles:\n")
+		for _, spec := range commands {
+			logs.WriteString(fmt.Sprintf("  %-8s %s\n", spec.Name, spec.Help))
+		}
+		logs.WriteString("Use 'help <comando>' para ver su sintaxis.")
+		return logs.String(), nil
+	}
+
+	name := strings.ToLower(cmd.Args[0].Value)
+	spec := findCommand(name)
+	if spec == nil {
+		return "", fmt.Errorf("comando desconocido: %s%s", name, didYouMean(name, commandNames(), ""))
+	}
+
+	var logs strings.Builder
+	logs.WriteString(fmt.Sprintf("%s: %s\n", spec.Name, spec.Help))
+	logs.WriteString("Sintaxis: " + spec.Usage())
+	for _, def := range spec.Params {
+		required := "opcional"
+		if def.Required {
+			required = "obligatorio"
+		}
+		line := fmt.Sprintf("\n  -%-13s %s, %s. %s", helpParamName(def), def.Type, required, def.Help)
+		if def.Default != "" {
+			line += fmt.Sprintf(" (por defecto: %s)", def.Default)
+		}
+		if def.Case == CaseLower {
+			line += " [se convierte a minúsculas]"
+		}
+		logs.WriteString(line)
+	}
+	return logs.String(), nil
+}
@@ -0,0 +1,858 @@
+package Analyzer
+
+import (
+	"backend/DiskManagement"
+	"backend/Structs"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Prefijo de los mensajes de una línea simulada
+const dryRunPrefix = "DRY-RUN: "
+
+// dryRunState es el modelo en memoria sobre el que se simula un script sin tocar los discos.
+// Los discos que ya existen se leen (solo lectura) la primera vez que se usan.
+type dryRunState struct {
+	disks   map[string]*dryRunDisk // Ruta -> disco; nil si el disco se eliminó en la simulación
+	trashed map[string]*dryRunDisk // Ruta en minúsculas -> disco que rmdisk movió a la papelera en la simulación
+	mounts  map[string][]DiskManagement.MountedPartition
+	ids     DiskManagement.IDAssignments // Letras y correlativos; los que se asignen en la simulación no se guardan
+}
+
+// dryRunDisk es la copia simulada de un disco
+type dryRunDisk struct {
+	size       int64
+	format     int32               // Structs.Format32 o Structs.Format64
+	fit        byte                // Ajuste del disco ('b', 'f' o 'w')
+	gpt        *DiskManagement.Gap // En un disco GPT, el espacio para particiones; nil si tiene MBR
+	partitions []*dryRunPartition  // Slots del MBR o entradas de la GPT; nil si están vacíos
+	logicals   []*dryRunPartition  // Particiones lógicas de la extendida, en orden de posición (ver addLogical)
+}
+
+// dryRunPartition es una partición simulada
+type dryRunPartition struct {
+	name  string
+	type_ byte  // 'p', 'e' o 'l'
+	fit   byte  // 'b', 'f' o 'w'
+	start int64 // En las lógicas, inicio de los datos (el EBR está justo antes)
+	size  int64
+	fs    *dryRunFS // nil si la partición no tiene sistema de archivos
+}
+
+// dryRunFS es el sistema de archivos simulado de una partición.
+// Si se formateó durante la simulación se conoce todo su contenido; si ya existía solo se sabe que existe.
+type dryRunFS struct {
+	known      bool
+	paths      map[string]bool // Ruta -> true si es carpeta, false si es archivo
+	users      []string        // Líneas de users.txt
+	freeInodes int32
+	freeBlocks int32
+	end        int64 // Posición donde terminan sus estructuras en el disco
+}
+
+// newDryRunState crea el modelo a partir de los montajes actuales
+func newDryRunState() *dryRunState {
+	return &dryRunState{
+		disks:   make(map[string]*dryRunDisk),
+		trashed: make(map[string]*dryRunDisk),
+		mounts:  DiskManagement.SnapshotMounts(),
+		ids:     DiskManagement.SnapshotIDAssignments(),
+	}
+}
+
+// disk devuelve el disco simulado de la ruta, leyéndolo del host si aún no se conoce
+func (s *dryRunState) disk(path string) (*dryRunDisk, error) {
+	if disk, ok := s.disks[path]; ok {
+		if disk == nil {
+			return nil, fmt.Errorf("el disco %s ya no existiría (se elimina en una línea anterior)", path)
+		}
+		return disk, nil
+	}
+
+	disk, err := loadDryRunDisk(path)
+	if err != nil {
+		return nil, err
+	}
+	s.disks[path] = disk
+	return disk, nil
+}
+
+// loadDryRunDisk lee el MBR, los EBR y los superbloques de un disco existente sin modificarlo
+func loadDryRunDisk(path string) (*dryRunDisk, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("el disco no existe en la ruta especificada: %s", path)
+	}
+	defer file.Close()
+
+	table, err := DiskManagement.ReadTable(file)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo leer el MBR de %s: %v", path, err)
+	}
+
+	mbr := table.MBR
+	disk := &dryRunDisk{size: mbr.MbrSize, format: mbr.Version, fit: mbr.Fit[0], partitions: make([]*dryRunPartition, len(table.Partitions))}
+	if table.GPT != nil {
+		disk.gpt = &DiskManagement.Gap{Start: table.DataStart(), Size: table.DataEnd() - table.DataStart()}
+	}
+	for i, part := range table.Partitions {
+		if part.Size == 0 {
+			continue
+		}
+		partition := &dryRunPartition{
+			name:  cString(part.Name[:]),
+			type_: part.Type[0],
+			fit:   part.Fit[0],
+			start: part.Start,
+			size:  part.Size,
+		}
+		if end := DiskManagement.FilesystemEnd(file, part.Start); partition.type_ != 'e' && end > 0 {
+			partition.fs = &dryRunFS{end: end}
+		}
+		disk.partitions[i] = partition
+
+		// Recorrer la cadena de EBR de la extendida
+		if partition.type_ == 'e' {
+			ebrPos := part.Start
+			for guard := 0; ebrPos >= 0 && guard < 1024; guard++ {
+				ebr, err := DiskManagement.ReadEBR(file, ebrPos)
+				if err != nil {
+					break
+				}
+				if ebr.PartSize > 0 {
+					logical := &dryRunPartition{name: cString(ebr.PartName[:]), type_: 'l', fit: ebr.PartFit, start: ebr.PartStart, size: ebr.PartSize}
+					if end := DiskManagement.FilesystemEnd(file, ebr.PartStart); end > 0 {
+						logical.fs = &dryRunFS{end: end}
+					}
+					disk.logicals = append(disk.logicals, logical)
+				}
+				ebrPos = ebr.PartNext
+			}
+		}
+	}
+	return disk, nil
+}
+
+// cString convierte un arreglo de bytes terminado en ceros a string
+func cString(b []byte) string {
+	return strings.TrimRight(string(b), "\x00")
+}
+
+// mounted busca la partición montada con el id y devuelve su disco y su partición simulados
+func (s *dryRunState) mounted(id string) (*DiskManagement.MountedPartition, *dryRunPartition, error) {
+	for diskID, partitions := range s.mounts {
+		for i := range partitions {
+			if !DiskManagement.SameID(partitions[i].ID, id) {
+				continue
+			}
+			disk, err := s.disk(partitions[i].Path)
+			if err != nil {
+				return nil, nil, err
+			}
+			partition := disk.find(partitions[i].Name)
+			if partition == nil {
+				return nil, nil, fmt.Errorf("la partición %s ya no existiría en %s", partitions[i].Name, partitions[i].Path)
+			}
+			return &s.mounts[diskID][i], partition, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("no se encontró ninguna partición montada con el ID %s", id)
+}
+
+// isMounted indica si la partición name del disco path está en la tabla de montajes simulada
+func (s *dryRunState) isMounted(path string, name string) bool {
+	for _, partition := range s.mounts[strings.ToLower(path)] {
+		if partition.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// session devuelve la partición con la sesión activa, o nil si no hay sesión
+func (s *dryRunState) session() *DiskManagement.MountedPartition {
+	for diskID, partitions := range s.mounts {
+		for i := range partitions {
+			if partitions[i].LoggedIn {
+				return &s.mounts[diskID][i]
+			}
+		}
+	}
+	return nil
+}
+
+// find busca una partición primaria, extendida o lógica por nombre
+func (d *dryRunDisk) find(name string) *dryRunPartition {
+	for _, partition := range d.partitions {
+		if partition != nil && partition.name == name {
+			return partition
+		}
+	}
+	for _, partition := range d.logicals {
+		if partition.name == name {
+			return partition
+		}
+	}
+	return nil
+}
+
+// gaps devuelve el mapa de espacio libre del disco simulado
+func (d *dryRunDisk) gaps() []DiskManagement.Gap {
+	var used []DiskManagement.Gap
+	for _, partition := range d.partitions {
+		if partition != nil {
+			used = append(used, DiskManagement.Gap{Start: partition.start, Size: partition.size})
+		}
+	}
+	if d.gpt != nil {
+		return DiskManagement.FreeGaps(d.gpt.Start, d.gpt.Start+d.gpt.Size, used)
+	}
+	return DiskManagement.FreeGaps(DiskManagement.MBRSize(d.format), d.size, used)
+}
+
+// extended devuelve la partición extendida del disco, o nil si no tiene
+func (d *dryRunDisk) extended() *dryRunPartition {
+	for _, partition := range d.partitions {
+		if partition != nil && partition.type_ == 'e' {
+			return partition
+		}
+	}
+	return nil
+}
+
+// addLogical agrega una lógica en orden de posición, como Fdisk la enlaza en la cadena de EBR; así
+// el índice de cada lógica da el mismo número de ID que le asigna Mount
+func (d *dryRunDisk) addLogical(partition *dryRunPartition) {
+	i := sort.Search(len(d.logicals), func(i int) bool { return d.logicals[i].start > partition.start })
+	d.logicals = slices.Insert(d.logicals, i, partition)
+}
+
+// logicalGaps devuelve los espacios libres de la extendida; cada lógica ocupa su EBR y sus datos
+func (d *dryRunDisk) logicalGaps(extended *dryRunPartition) []DiskManagement.Gap {
+	ebrSize := DiskManagement.EBRSize(d.format)
+	var used []DiskManagement.Gap
+	for _, partition := range d.logicals {
+		used = append(used, DiskManagement.Gap{Start: partition.start - ebrSize, Size: ebrSize + partition.size})
+	}
+	return DiskManagement.FreeGaps(extended.start, extended.start+extended.size, used)
+}
+
+// sizeInBytes convierte el tamaño con la unidad de mkdisk/fdisk a bytes
+func sizeInBytes(size int, unit string) int64 {
+	switch unit {
+	case "k":
+		return int64(size) * 1024
+	case "m":
+		return int64(size) * 1024 * 1024
+	default:
+		return int64(size)
+	}
+}
+
+func dry_mkdisk(s *dryRunState, cmd Structs.Command) (string, error) {
+	path := cmd.Value("path")
+	size := sizeInBytes(cmd.Int("size"), cmd.Value("unit"))
+	format := Structs.Format64
+	if cmd.Value("format") == "32" && cmd.Value("table") != "gpt" {
+		format = Structs.Format32
+		if size > math.MaxInt32 {
+			return "", fmt.Errorf("un disco de %d bytes no cabría en el formato de 32 bits; use -format=64", size)
+		}
+	}
+	disk := &dryRunDisk{size: size, format: format, fit: cmd.Value("fit")[0], partitions: make([]*dryRunPartition, 4)}
+	if cmd.Value("table") == "gpt" {
+		if err := DiskManagement.CheckEntryIDs(cmd.Int("entries")); err != nil {
+			return "", err
+		}
+		// Se arma la GPT en memoria solo para saber qué espacio deja para las particiones
+		table, err := DiskManagement.NewGPTTable(Structs.MBR{MbrSize: size}, cmd.Int("entries"))
+		if err != nil {
+			return "", err
+		}
+		disk.gpt = &DiskManagement.Gap{Start: table.DataStart(), Size: table.DataEnd() - table.DataStart()}
+		disk.partitions = make([]*dryRunPartition, cmd.Int("entries"))
+	}
+	s.disks[path] = disk
+	if cmd.Value("zero") == "none" {
+		return fmt.Sprintf("%smkdisk crearía el disco disperso %s de %d bytes", dryRunPrefix, path, size), nil
+	}
+	return fmt.Sprintf("%smkdisk crearía el disco %s de %d bytes", dryRunPrefix, path, size), nil
+}
+
+func dry_rmdisk(s *dryRunState, cmd Structs.Command) (string, error) {
+	path := cmd.Value("path")
+	if err := rmdiskConfirm(cmd); err != nil {
+		return "", err
+	}
+	disk, err := s.disk(path)
+	if err != nil {
+		return "", err
+	}
+	if mounted := s.mounts[strings.ToLower(path)]; len(mounted) > 0 {
+		if !cmd.Has("force") {
+			return "", fmt.Errorf("el disco %s tiene %d partición(es) montada(s); desmóntelas o use -force", path, len(mounted))
+		}
+		delete(s.mounts, strings.ToLower(path))
+	}
+	s.disks[path] = nil
+	s.trashed[strings.ToLower(path)] = disk
+	return fmt.Sprintf("%srmdisk movería el disco %s a la papelera", dryRunPrefix, path), nil
+}
+
+// dry_restoredisk devuelve al modelo un disco eliminado en la simulación o lee (solo lectura) el de la papelera
+func dry_restoredisk(s *dryRunState, cmd Structs.Command) (string, error) {
+	path := cmd.Value("path")
+	if disk, ok := s.disks[path]; ok && disk != nil {
+		return "", fmt.Errorf("ya existiría un disco en %s", path)
+	}
+	if disk := s.trashed[strings.ToLower(path)]; disk != nil {
+		delete(s.trashed, strings.ToLower(path))
+		s.disks[path] = disk
+		return fmt.Sprintf("%srestoredisk restauraría el disco %s", dryRunPrefix, path), nil
+	}
+
+	entry, err := DiskManagement.FindTrashEntry(path, cmd.Value("entry"))
+	if err != nil {
+		return "", err
+	}
+	if _, ok := s.disks[path]; !ok {
+		if _, err := os.Stat(entry.Path); err == nil {
+			return "", fmt.Errorf("ya existe un disco en %s", entry.Path)
+		}
+	}
+	disk, err := loadDryRunDisk(entry.File())
+	if err != nil {
+		return "", err
+	}
+	s.disks[path] = disk
+	return fmt.Sprintf("%srestoredisk restauraría el disco %s (entrada %s)", dryRunPrefix, path, entry.ID), nil
+}
+
+// dry_convertdisk recalcula el modelo del disco como lo deja ConvertDisk: el MBR, los EBR y los
+// superbloques crecen y todo lo que está después se corre
+func dry_convertdisk(s *dryRunState, cmd Structs.Command) (string, error) {
+	path := cmd.Value("path")
+	disk, err := s.disk(path)
+	if err != nil {
+		return "", err
+	}
+	if len(s.mounts[strings.ToLower(path)]) > 0 {
+		return "", fmt.Errorf("el disco %s tiene particiones montadas; desmóntelas antes de convertirlo", path)
+	}
+	if disk.format != Structs.Format32 {
+		return "", fmt.Errorf("el disco %s ya está en el formato de 64 bits", path)
+	}
+
+	var shift DiskManagement.FormatShift
+	shift.Grow(0, DiskManagement.MBRSize(Structs.Format32), DiskManagement.MBRSize(Structs.Format64))
+	ebrSize := DiskManagement.EBRSize(Structs.Format32)
+	growFS := func(partition *dryRunPartition) {
+		if partition.fs != nil {
+			shift.Grow(partition.start, DiskManagement.SuperblockSize(Structs.Format32), DiskManagement.SuperblockSize(Structs.Format64))
+		}
+	}
+	for _, partition := range disk.partitions {
+		if partition != nil && partition.type_ != 'e' {
+			growFS(partition)
+		}
+	}
+	if extended := disk.extended(); extended != nil {
+		// El EBR inicial queda vacío si ninguna lógica lo ocupa
+		head := true
+		for _, logical := range disk.logicals {
+			head = head && logical.start-ebrSize != extended.start
+			shift.Grow(logical.start-ebrSize, ebrSize, DiskManagement.EBRSize(Structs.Format64))
+			growFS(logical)
+		}
+		if head {
+			shift.Grow(extended.start, ebrSize, DiskManagement.EBRSize(Structs.Format64))
+		}
+	}
+
+	move := func(partition *dryRunPartition) {
+		end := shift.Map(partition.start + partition.size)
+		partition.start = shift.Map(partition.start)
+		partition.size = end - partition.start
+		if partition.fs != nil {
+			partition.fs.end = shift.Map(partition.fs.end)
+		}
+	}
+	for _, partition := range disk.partitions {
+		if partition != nil {
+			move(partition)
+		}
+	}
+	for _, logical := range disk.logicals {
+		move(logical)
+	}
+	disk.size += shift.Total()
+	disk.format = Structs.Format64
+	return fmt.Sprintf("%sconvertdisk convertiría el disco %s al formato de 64 bits (%d bytes)", dryRunPrefix, path, disk.size), nil
+}
+
+// dry_exportmbr valida que el disco quepa en un MBR de DOS; la imagen no forma parte del modelo
+func dry_exportmbr(s *dryRunState, cmd Structs.Command) (string, error) {
+	path := cmd.Value("path")
+	image := cmd.Value("image")
+	disk, err := s.disk(path)
+	if err != nil {
+		return "", err
+	}
+	if disk.gpt != nil {
+		used := 0
+		for _, partition := range disk.partitions {
+			if partition != nil {
+				used++
+			}
+		}
+		if used > 4 {
+			return "", fmt.Errorf("el disco %s tiene %d particiones en su GPT y el MBR de DOS solo admite 4 primarias", path, used)
+		}
+	}
+	if _, err := os.Stat(image); err == nil {
+		return "", fmt.Errorf("ya existe un archivo en %s", image)
+	}
+	return fmt.Sprintf("%sexportmbr exportaría el disco %s a la imagen %s", dryRunPrefix, path, image), nil
+}
+
+// dry_importmbr lee la tabla de la imagen (solo lectura) y agrega al modelo el disco que se crearía
+func dry_importmbr(s *dryRunState, cmd Structs.Command) (string, error) {
+	image := cmd.Value("image")
+	path := cmd.Value("path")
+	if disk, ok := s.disks[path]; ok && disk != nil {
+		return "", fmt.Errorf("ya existiría un disco en %s", path)
+	}
+	if _, ok := s.disks[path]; !ok {
+		if _, err := os.Stat(path); err == nil {
+			return "", fmt.Errorf("ya existe un disco en %s", path)
+		}
+	}
+	file, err := os.Open(image)
+	if err != nil {
+		return "", fmt.Errorf("la imagen no existe en la ruta especificada: %s", image)
+	}
+	defer file.Close()
+	dos, err := DiskManagement.ReadDOSImage(file)
+	if err != nil {
+		return "", err
+	}
+
+	disk := &dryRunDisk{size: dos.Size, format: dos.Format, fit: dos.Fit, partitions: make([]*dryRunPartition, 4)}
+	model := func(partition DiskManagement.DOSImagePartition) *dryRunPartition {
+		modeled := &dryRunPartition{name: partition.Name, type_: partition.Type, fit: partition.Fit, start: partition.Start, size: partition.Size}
+		if partition.FSEnd > 0 {
+			modeled.fs = &dryRunFS{end: partition.FSEnd}
+		}
+		return modeled
+	}
+	for _, partition := range dos.Partitions {
+		disk.partitions[partition.Slot] = model(partition)
+	}
+	for _, logical := range dos.Logicals {
+		disk.logicals = append(disk.logicals, model(logical))
+	}
+	s.disks[path] = disk
+	return fmt.Sprintf("%simportmbr crearía el disco %s de %d bytes a partir de la imagen %s", dryRunPrefix, path, disk.size, image), nil
+}
+
+func dry_fdisk(s *dryRunState, cmd Structs.Command) (string, error) {
+	path := cmd.Value("path")
+	name := cmd.Value("name")
+	type_ := cmd.Value("type")
+	size := sizeInBytes(cmd.Int("size"), cmd.Value("unit"))
+
+	if err := fdiskMode(cmd); err != nil {
+		return "", err
+	}
+	disk, err := s.disk(path)
+	if err != nil {
+		return "", err
+	}
+	if cmd.Has("delete") {
+		return s.deletePartition(disk, path, name)
+	}
+	if cmd.Has("add") {
+		return disk.resize(path, name, sizeInBytes(cmd.Int("add"), cmd.Value("unit")))
+	}
+
+	// Mismas validaciones que Fdisk sobre los slots del MBR o las entradas de la GPT
+	var extendedCount, totalPartitions int
+	for _, partition := range disk.partitions {
+		if partition == nil {
+			continue
+		}
+		totalPartitions++
+		if partition.type_ == 'e' {
+			extendedCount++
+		}
+	}
+	if disk.gpt != nil {
+		if type_ != "p" {
+			return "", fmt.Errorf("la partición %s no sería primaria y %s tiene una tabla GPT", name, path)
+		}
+		if totalPartitions >= len(disk.partitions) {
+			return "", fmt.Errorf("las %d entradas de la GPT de %s estarían ocupadas", len(disk.partitions), path)
+		}
+	}
+	if type_ != "l" && totalPartitions >= 4 && disk.gpt == nil {
+		return "", fmt.Errorf("no se podrían crear más de 4 particiones primarias o extendidas en %s", path)
+	}
+	if type_ == "e" && extendedCount > 0 {
+		return "", fmt.Errorf("la partición %s sería una segunda extendida en %s", name, path)
+	}
+	if type_ == "l" && extendedCount == 0 {
+		return "", fmt.Errorf("la partición lógica %s no tendría una partición extendida en %s", name, path)
+	}
+	partition := &dryRunPartition{name: name, type_: type_[0], fit: cmd.Value("fit")[0], size: size}
+	if type_ == "l" {
+		// Se coloca en un espacio libre de la extendida según su ajuste, con su EBR antes de los datos
+		extended := disk.extended()
+		ebrSize := DiskManagement.EBRSize(disk.format)
+		chosen, err := DiskManagement.PlaceLogical(disk.logicalGaps(extended), ebrSize, size, extended.fit, name)
+		if err != nil {
+			return "", err
+		}
+		partition.start = chosen.Start + ebrSize
+		disk.addLogical(partition)
+		return fmt.Sprintf("%sfdisk crearía la partición lógica %s de %d bytes en %s", dryRunPrefix, name, size, path), nil
+	}
+
+	// Se coloca en un espacio libre según el ajuste del disco, igual que Fdisk
+	chosen, err := DiskManagement.PlacePartition(disk.gaps(), size, disk.fit, name)
+	if err != nil {
+		return "", err
+	}
+	partition.start = chosen.Start
+	for i := range disk.partitions {
+		if disk.partitions[i] == nil {
+			disk.partitions[i] = partition
+			break
+		}
+	}
+	return fmt.Sprintf("%sfdisk crearía la partición %s (%s) de %d bytes en %s", dryRunPrefix, name, type_, size, path), nil
+}
+
+// deletePartition quita la partición del modelo con las mismas reglas que DeletePartition
+func (s *dryRunState) deletePartition(disk *dryRunDisk, path string, name string) (string, error) {
+	for i, partition := range disk.partitions {
+		if partition == nil || partition.name != name {
+			continue
+		}
+		if s.isMounted(path, name) {
+			return "", fmt.Errorf("la partición %s está montada; desmóntela antes de eliminarla", name)
+		}
+		if partition.type_ == 'e' {
+			for _, logical := range disk.logicals {
+				if s.isMounted(path, logical.name) {
+					return "", fmt.Errorf("la partición lógica %s de la extendida %s está montada; desmóntela antes de eliminarla", logical.name, name)
+				}
+			}
+			disk.logicals = nil
+		}
+		disk.partitions[i] = nil
+		return fmt.Sprintf("%sfdisk eliminaría la partición %s de %s", dryRunPrefix, name, path), nil
+	}
+
+	for i, logical := range disk.logicals {
+		if logical.name != name {
+			continue
+		}
+		if s.isMounted(path, name) {
+			return "", fmt.Errorf("la partición %s está montada; desmóntela antes de eliminarla", name)
+		}
+		disk.logicals = append(disk.logicals[:i], disk.logicals[i+1:]...)
+		return fmt.Sprintf("%sfdisk eliminaría la partición lógica %s de %s", dryRunPrefix, name, path), nil
+	}
+	return "", fmt.Errorf("no existe la partición %s en %s", name, path)
+}
+
+// resize cambia el tamaño de la partición en el modelo con las mismas reglas que ResizePartition (CheckResize)
+func (d *dryRunDisk) resize(path string, name string, delta int64) (string, error) {
+	partition := d.find(name)
+	if partition == nil {
+		return "", fmt.Errorf("no existe la partición %s en %s", name, path)
+	}
+
+	// Lo que la partición no puede cortar al reducirse y de dónde puede crecer
+	var minEnd int64
+	var gaps []DiskManagement.Gap
+	switch {
+	case partition.type_ == 'l':
+		gaps = d.logicalGaps(d.extended())
+	case partition.type_ == 'e':
+		gaps = d.gaps()
+		minEnd = partition.start + DiskManagement.EBRSize(d.format)
+		for _, logical := range d.logicals {
+			minEnd = max(minEnd, logical.start+logical.size)
+		}
+	default:
+		gaps = d.gaps()
+	}
+	if partition.fs != nil {
+		minEnd = partition.fs.end
+	}
+
+	newSize, err := DiskManagement.CheckResize(name, partition.start, partition.size, delta, minEnd, gaps)
+	if err != nil {
+		return "", err
+	}
+
+	oldSize := partition.size
+	partition.size = newSize
+	return fmt.Sprintf("%sfdisk redimensionaría la partición %s de %d a %d bytes en %s", dryRunPrefix, name, oldSize, newSize, path), nil
+}
+
+func dry_mount(s *dryRunState, cmd Structs.Command) (string, error) {
+	path := cmd.Value("path")
+	name := cmd.Value("name")
+
+	disk, err := s.disk(path)
+	if err != nil {
+		return "", err
+	}
+
+	// Mismo número que usa Mount: el slot para las primarias y 5, 6, ... para las lógicas
+	number, length := 0, 0
+	for i, partition := range disk.partitions {
+		if partition != nil && partition.type_ == 'p' && partition.name == name {
+			number, length = i+1, DiskManagement.MountIDLength(disk.format, false)
+			break
+		}
+	}
+	for i, logical := range disk.logicals {
+		if number == 0 && logical.name == name {
+			number, length = DiskManagement.LogicalNumber(i), DiskManagement.MountIDLength(disk.format, true)
+		}
+	}
+	if number == 0 {
+		return "", fmt.Errorf("partición %s no encontrada o es una partición extendida", name)
+	}
+	if s.isMounted(path, name) {
+		return "", fmt.Errorf("la partición %s ya está montada", name)
+	}
+
+	id, err := DiskManagement.NextMountID(s.ids, s.mounts, path, name, number, length)
+	if err != nil {
+		return "", err
+	}
+	diskID := strings.ToLower(path)
+	s.mounts[diskID] = append(s.mounts[diskID], DiskManagement.MountedPartition{Path: path, Name: name, ID: id, Status: '1'})
+	return fmt.Sprintf("%smount montaría la partición %s con ID %s", dryRunPrefix, name, id), nil
+}
+
+func dry_unmount(s *dryRunState, cmd Structs.Command) (string, error) {
+	id := cmd.Value("id")
+	for diskID, partitions := range s.mounts {
+		for i, mount := range partitions {
+			if !DiskManagement.SameID(mount.ID, id) {
+				continue
+			}
+			s.mounts[diskID] = append(partitions[:i:i], partitions[i+1:]...)
+			return fmt.Sprintf("%sunmount desmontaría la partición %s (%s)", dryRunPrefix, mount.Name, id), nil
+		}
+	}
+	return "", fmt.Errorf("no hay ninguna partición montada con el ID %s", id)
+}
+
+func dry_mkfs(s *dryRunState, cmd Structs.Command) (string, error) {
+	id := cmd.Value("id")
+	mount, partition, err := s.mounted(id)
+	if err != nil {
+		return "", err
+	}
+	disk, err := s.disk(mount.Path)
+	if err != nil {
+		return "", err
+	}
+
+	// Mismo cálculo de inodos que Mkfs
+	superblockSize := DiskManagement.SuperblockSize(disk.format)
+	numerador := partition.size - superblockSize
+	denominador := int64(4 + binary.Size(Structs.Inode{}) + 3*binary.Size(Structs.Fileblock{}))
+	if numerador/denominador > math.MaxInt32/3 {
+		return "", fmt.Errorf("la partición %s es demasiado grande para EXT2", id)
+	}
+	n := int32(numerador / denominador)
+	if n < 2 {
+		return "", fmt.Errorf("la partición %s es demasiado pequeña para un sistema de archivos EXT2", id)
+	}
+
+	// Un sistema recién formateado tiene la raíz y /users.txt
+	partition.fs = &dryRunFS{
+		known:      true,
+		paths:      map[string]bool{"/": true, "/users.txt": false},
+		users:      []string{"1,G,root", "1,U,root,root,123"},
+		freeInodes: n - 2,
+		freeBlocks: 3*n - 2,
+		end:        partition.start + superblockSize + int64(n)*denominador,
+	}
+	return fmt.Sprintf("%smkfs formatearía la partición %s con %d inodos", dryRunPrefix, id, n), nil
+}
+
+func dry_login(s *dryRunState, cmd Structs.Command) (string, error) {
+	id := cmd.Value("id")
+	mount, partition, err := s.mounted(id)
+	if err != nil {
+		return "", err
+	}
+	if mount.LoggedIn {
+		return "", fmt.Errorf("ya existe un usuario logueado en la partición %s", id)
+	}
+	if partition.fs == nil {
+		return "", fmt.Errorf("la partición %s no tendría un sistema de archivos (falta mkfs)", id)
+	}
+
+	// Solo se pueden revisar las credenciales de un sistema formateado en la simulación
+	if partition.fs.known {
+		login := false
+		for _, line := range partition.fs.users {
+			words := strings.Split(line, ",")
+			if len(words) == 5 && strings.Contains(words[3], cmd.Value("user")) && strings.Contains(words[4], cmd.Value("pass")) {
+				login = true
+				break
+			}
+		}
+		if !login {
+			return "", fmt.Errorf("usuario o contraseña incorrectos")
+		}
+	}
+
+	mount.LoggedIn = true
+	return fmt.Sprintf("%slogin iniciaría sesión en %s", dryRunPrefix, id), nil
+}
+
+func dry_logout(s *dryRunState, cmd Structs.Command) (string, error) {
+	mount := s.session()
+	if mount == nil {
+		return "", fmt.Errorf("no hay una sesión activa actualmente")
+	}
+	mount.LoggedIn = false
+	return dryRunPrefix + "logout cerraría la sesión", nil
+}
+
+// sessionFS devuelve el sistema de archivos de la partición con sesión activa
+func (s *dryRunState) sessionFS() (*dryRunFS, error) {
+	mount := s.session()
+	if mount == nil {
+		return nil, fmt.Errorf("no hay ninguna partición logueada")
+	}
+	_, partition, err := s.mounted(mount.ID)
+	if err != nil {
+		return nil, err
+	}
+	if partition.fs == nil {
+		return nil, fmt.Errorf("la partición %s no tendría un sistema de archivos (falta mkfs)", mount.ID)
+	}
+	return partition.fs, nil
+}
+
+// mkdirAll crea en el modelo las carpetas de la ruta que falten, como lo hacen mkdir y mkfile
+func (fs *dryRunFS) mkdirAll(dirs []string) error {
+	current := "/"
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		current = filepath.Join(current, dir)
+		isDir, exists := fs.paths[current]
+		if exists && !isDir {
+			return fmt.Errorf("%s es un archivo, no una carpeta", current)
+		}
+		if exists {
+			continue
+		}
+		if fs.freeInodes < 1 || fs.freeBlocks < 1 {
+			return fmt.Errorf("no quedarían inodos o bloques libres para crear %s", current)
+		}
+		fs.freeInodes--
+		fs.freeBlocks--
+		fs.paths[current] = true
+	}
+	return nil
+}
+
+func dry_mkdir(s *dryRunState, cmd Structs.Command) (string, error) {
+	path := cmd.Value("path")
+	fs, err := s.sessionFS()
+	if err != nil {
+		return "", err
+	}
+	if fs.known {
+		if err := fs.mkdirAll(strings.Split(path, "/")); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%smkdir crearía la carpeta %s", dryRunPrefix, path), nil
+}
+
+func dry_mkfile(s *dryRunState, cmd Structs.Command) (string, error) {
+	path := cmd.Value("path")
+	fs, err := s.sessionFS()
+	if err != nil {
+		return "", err
+	}
+	if fs.known {
+		dirs := strings.Split(path, "/")
+		if err := fs.mkdirAll(dirs[:len(dirs)-1]); err != nil {
+			return "", err
+		}
+
+		file := filepath.Join("/", path)
+		if isDir, exists := fs.paths[file]; exists && isDir {
+			return "", fmt.Errorf("%s es una carpeta, no un archivo", file)
+		}
+
+		// Un inodo y los bloques necesarios para el contenido
+		size := cmd.Int("size")
+		if cont, ok := cmd.Get("cont"); ok {
+			size = len(cont)
+		}
+		blockSize := binary.Size(Structs.Fileblock{})
+		blocks := int32((size + blockSize - 1) / blockSize)
+		if fs.freeInodes < 1 || fs.freeBlocks < blocks {
+			return "", fmt.Errorf("no quedarían inodos o bloques libres para crear %s", file)
+		}
+		fs.freeInodes--
+		fs.freeBlocks -= blocks
+		fs.paths[file] = false
+	}
+	return fmt.Sprintf("%smkfile crearía el archivo %s", dryRunPrefix, path), nil
+}
+
+func dry_cat(s *dryRunState, cmd Structs.Command) (string, error) {
+	return dryRunPrefix + "cat mostraría el contenido de los archivos", nil
+}
+
+func dry_rep(s *dryRunState, cmd Structs.Command) (string, error) {
+	name := cmd.Value("name")
+	id := cmd.Value("id")
+
+	// Mismas validaciones que fn_rep, sin generar el reporte
+	if (name == "file" || name == "ls") && cmd.Value("path_file_ls") == "" {
+		return "", fmt.Errorf("falta el parámetro -path_file_ls para el reporte %s", name)
+	}
+	if _, _, err := s.mounted(id); err != nil {
+		return "", fmt.Errorf("partición no encontrada: %s", id)
+	}
+	switch name {
+	case "mbr", "disk", "inode", "block", "sb":
+	default:
+		return "", fmt.Errorf("el reporte %s aún no está implementado", name)
+	}
+	return fmt.Sprintf("%srep generaría el reporte %s en %s", dryRunPrefix, name, cmd.Value("path")), nil
+}
+
+func dry_clear(s *dryRunState, cmd Structs.Command) (string, error) {
+	return dryRunPrefix + "clear limpiaría la terminal", nil
+}
+
+// help no modifica nada, así que en la simulación se ejecuta tal cual
+func dry_help(s *dryRunState, cmd Structs.Command) (string, error) {
+	return fn_help(cmd)
+}
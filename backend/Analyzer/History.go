@@ -0,0 +1,365 @@
+package Analyzer
+
+import (
+	"backend/DiskManagement"
+	"backend/Structs"
+	"backend/User"
+	"backend/Utilities"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Nombre del archivo del historial dentro de la carpeta de datos
+const journalFile = "history.jsonl"
+
+// JournalEntry es un comando ejecutado, tal como queda guardado en el historial
+type JournalEntry struct {
+	Seq       int       `json:"seq"`                 // Número de entrada (1-based)
+	Time      time.Time `json:"time"`                // Momento en que terminó el comando
+	Command   string    `json:"command"`             // Línea ejecutada, sin el comentario final
+	Name      string    `json:"name"`                // Nombre del comando
+	Disk      string    `json:"disk,omitempty"`      // Disco sobre el que se aplicó
+	Partition string    `json:"partition,omitempty"` // ID de la partición montada, si aplica
+	User      string    `json:"user,omitempty"`      // Usuario con sesión activa
+	Status    string    `json:"status"`              // StatusOK o StatusError
+	Error     string    `json:"error,omitempty"`     // Texto del error si falló
+	Source    string    `json:"source,omitempty"`    // archivo:línea de donde vino el comando
+}
+
+// Último número de entrada usado; -1 mientras no se ha leído el historial
+var lastJournalSeq = -1
+
+// Mientras replay vuelve a ejecutar comandos, estos no se agregan al historial
+var journalPaused bool
+
+// journalPath devuelve la ruta del archivo del historial
+func journalPath() string {
+	return filepath.Join(Utilities.DataDir(), journalFile)
+}
+
+// journalTarget devuelve el disco y la partición a los que se aplica el comando; se llama antes de ejecutarlo
+func journalTarget(spec *CommandSpec, cmd Structs.Command) (string, string) {
+	if spec.DiskPath {
+		return cmd.Value("path"), ""
+	}
+	id := cmd.Value("id")
+	if id == "" {
+		id = User.CurrentLoggedPartitionID
+	}
+	if partition := DiskManagement.GetPartitionByID(id); partition != nil {
+		return partition.Path, id
+	}
+	return "", id
+}
+
+// runCommand ejecuta el comando y lo agrega al historial con su resultado
+func runCommand(spec *CommandSpec, cmd Structs.Command, source string) (string, error) {
+	if spec.NoJournal || journalPaused {
+		return spec.Run(cmd)
+	}
+
+	disk, id := journalTarget(spec, cmd)
+	user := User.CurrentUser
+
+	message, err := spec.Run(cmd)
+
+	// login deja el usuario hasta después de ejecutarse
+	if user == "" {
+		user = User.CurrentUser
+	}
+	entry := JournalEntry{Time: time.Now(), Command: cmd.Raw, Name: spec.Name, Disk: disk, Partition: id, User: user, Status: StatusOK, Source: source}
+	if err != nil {
+		entry.Status = StatusError
+		entry.Error = err.Error()
+	}
+	if journalErr := appendJournal(entry); journalErr != nil {
+		// El historial nunca debe hacer fallar un comando
+		fmt.Println("Error al escribir el historial:", journalErr)
+	}
+	return message, err
+}
+
+// appendJournal agrega una entrada al final del historial
+func appendJournal(entry JournalEntry) error {
+	if lastJournalSeq < 0 {
+		entries, err := readJournal()
+		if err != nil {
+			return err
+		}
+		lastJournalSeq = len(entries)
+	}
+
+	if err := os.MkdirAll(Utilities.DataDir(), os.ModePerm); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(journalPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	entry.Seq = lastJournalSeq + 1
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	lastJournalSeq = entry.Seq
+	return nil
+}
+
+// readJournal lee todas las entradas del historial; si aún no existe devuelve una lista vacía
+func readJournal() ([]JournalEntry, error) {
+	file, err := os.Open(journalPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("entrada inválida en el historial: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// journalBound es un límite de -from o -to: un número de entrada o una fecha
+type journalBound struct {
+	seq  int
+	time time.Time
+	end  time.Time // Para fechas sin hora, el final del día
+}
+
+// Formatos de fecha aceptados en -from y -to
+var journalTimeFormats = []string{"2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+// parseJournalBound interpreta un valor de -from o -to
+func parseJournalBound(value string) (journalBound, error) {
+	if seq, err := strconv.Atoi(value); err == nil {
+		return journalBound{seq: seq}, nil
+	}
+	for _, format := range journalTimeFormats {
+		if t, err := time.ParseInLocation(format, value, time.Local); err == nil {
+			bound := journalBound{time: t, end: t}
+			if format == "2006-01-02" {
+				bound.end = t.Add(24*time.Hour - time.Nanosecond)
+			}
+			return bound, nil
+		}
+	}
+	return journalBound{}, fmt.Errorf("valor inválido '%s': se esperaba un número de entrada o una fecha AAAA-MM-DD [HH:MM[:SS]]", value)
+}
+
+// after indica si la entrada está en o después del límite
+func (b journalBound) after(entry JournalEntry) bool {
+	if b.seq > 0 {
+		return entry.Seq >= b.seq
+	}
+	return !entry.Time.Before(b.time)
+}
+
+// before indica si la entrada está en o antes del límite
+func (b journalBound) before(entry JournalEntry) bool {
+	if b.seq > 0 {
+		return entry.Seq <= b.seq
+	}
+	return !entry.Time.After(b.end)
+}
+
+// filterJournal devuelve las entradas entre -from y -to (si se dieron)
+func filterJournal(entries []JournalEntry, cmd Structs.Command) ([]JournalEntry, error) {
+	var from, to *journalBound
+	if value, ok := cmd.Get("from"); ok {
+		bound, err := parseJournalBound(value)
+		if err != nil {
+			return nil, err
+		}
+		from = &bound
+	}
+	if value, ok := cmd.Get("to"); ok {
+		bound, err := parseJournalBound(value)
+		if err != nil {
+			return nil, err
+		}
+		to = &bound
+	}
+
+	var filtered []JournalEntry
+	for _, entry := range entries {
+		if (from == nil || from.after(entry)) && (to == nil || to.before(entry)) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered, nil
+}
+
+// String da una línea legible de la entrada
+func (entry JournalEntry) String() string {
+	line := fmt.Sprintf("#%d %s [%s] %s", entry.Seq, entry.Time.Format("2006-01-02 15:04:05"), entry.Status, entry.Command)
+	var details []string
+	if entry.User != "" {
+		details = append(details, "usuario="+entry.User)
+	}
+	if entry.Partition != "" {
+		details = append(details, "id="+entry.Partition)
+	}
+	if entry.Disk != "" {
+		details = append(details, "disco="+entry.Disk)
+	}
+	if len(details) > 0 {
+		line += " (" + strings.Join(details, ", ") + ")"
+	}
+	if entry.Error != "" {
+		line += " -> " + entry.Error
+	}
+	return line
+}
+
+func fn_history(cmd Structs.Command) (string, error) {
+	entries, err := readJournal()
+	if err != nil {
+		return "", err
+	}
+	entries, err = filterJournal(entries, cmd)
+	if err != nil {
+		return "", err
+	}
+
+	disk := cmd.Value("disk")
+	user := cmd.Value("user")
+	var lines []string
+	for _, entry := range entries {
+		if disk != "" && entry.Disk != disk {
+			continue
+		}
+		if user != "" && entry.User != user {
+			continue
+		}
+		lines = append(lines, entry.String())
+	}
+
+	if len(lines) == 0 {
+		return "HISTORY: No hay comandos que coincidan con los filtros", nil
+	}
+	return fmt.Sprintf("HISTORY: %d comando(s)\n%s", len(lines), strings.Join(lines, "\n")), nil
+}
+
+// history solo lee el historial, así que en la simulación se ejecuta tal cual
+func dry_history(s *dryRunState, cmd Structs.Command) (string, error) {
+	return fn_history(cmd)
+}
+
+func fn_replay(cmd Structs.Command) (string, error) {
+	entries, err := readJournal()
+	if err != nil {
+		return "", err
+	}
+	entries, err = filterJournal(entries, cmd)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "", fmt.Errorf("no hay comandos en el historial entre -from y -to")
+	}
+
+	// Los discos se crean en una carpeta nueva para no tocar los originales
+	dir := cmd.Value("dir")
+	if dir == "" {
+		dir = filepath.Join(Utilities.DataDir(), "replay", time.Now().Format("20060102-150405"))
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("no se pudo crear la carpeta %s: %v", dir, err)
+	}
+
+	// La repetición empieza sin montajes, letras asignadas ni sesión; al terminar se restaura el estado actual.
+	// Mientras tanto la carpeta de datos es dir: la tabla de montajes y la papelera de la repetición quedan
+	// ahí y las reales no se tocan aunque la repetición se interrumpa.
+	mounts, ids := DiskManagement.SnapshotMounts(), DiskManagement.SnapshotIDAssignments()
+	session, user := User.CurrentLoggedPartitionID, User.CurrentUser
+	restoreDataDir := Utilities.SetDataDir(dir)
+	DiskManagement.RestoreMounts(make(map[string][]DiskManagement.MountedPartition))
+	DiskManagement.RestoreIDAssignments(make(DiskManagement.IDAssignments))
+	User.CurrentLoggedPartitionID, User.CurrentUser = "", ""
+	journalPaused = true
+	defer func() {
+		restoreDataDir()
+		DiskManagement.RestoreMounts(mounts)
+		DiskManagement.RestoreIDAssignments(ids)
+		User.CurrentLoggedPartitionID, User.CurrentUser = session, user
+		journalPaused = false
+	}()
+
+	var lines []string
+	failed := 0
+	for _, entry := range entries {
+		message, err := replayEntry(entry, dir)
+		if err != nil {
+			failed++
+			lines = append(lines, fmt.Sprintf("#%d %s -> Error: %v", entry.Seq, entry.Command, err))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("#%d %s -> %s", entry.Seq, entry.Command, lastLine(message)))
+	}
+
+	lines = append(lines, fmt.Sprintf("REPLAY: %d comando(s) repetidos con %d error(es); discos en %s", len(entries), failed, dir))
+	return strings.Join(lines, "\n"), nil
+}
+
+// replay solo crearía discos en su propia carpeta; en la simulación basta con revisar el tramo
+func dry_replay(s *dryRunState, cmd Structs.Command) (string, error) {
+	entries, err := readJournal()
+	if err != nil {
+		return "", err
+	}
+	entries, err = filterJournal(entries, cmd)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "", fmt.Errorf("no hay comandos en el historial entre -from y -to")
+	}
+	return fmt.Sprintf("%sreplay repetiría %d comando(s) sobre discos nuevos", dryRunPrefix, len(entries)), nil
+}
+
+// replayEntry vuelve a ejecutar una entrada con sus rutas del host redirigidas a dir
+func replayEntry(entry JournalEntry, dir string) (string, error) {
+	spec, cmd, _, err := prepare(entry.Command, Options{})
+	if err != nil {
+		return "", err
+	}
+	for i, param := range cmd.Params {
+		if def := spec.findParam(param.Name); def != nil && def.HostPath {
+			cmd.Params[i].Value = filepath.Join(dir, strings.TrimPrefix(filepath.Clean(param.Value), string(filepath.Separator)))
+		}
+	}
+	return spec.Run(cmd)
+}
+
+// lastLine devuelve la última línea de la salida de un comando (su mensaje final)
+func lastLine(message string) string {
+	message = strings.TrimRight(message, "\n")
+	if i := strings.LastIndex(message, "\n"); i >= 0 {
+		return message[i+1:]
+	}
+	return message
+}
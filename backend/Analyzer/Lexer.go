@@ -0,0 +1,135 @@
+package Analyzer
+
+import (
+	"backend/Structs"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Lex convierte una línea en un comando tipado.
+// Reconoce valores entre comillas con espacios, comillas escapadas (\"), "=" dentro
+// de los valores, valores vacíos (-name= o -name="") y comentarios que inician con #.
+func Lex(input string) (Structs.Command, error) {
+	var cmd Structs.Command
+	runes := []rune(input)
+	i := 0
+
+	for {
+		// Saltar espacios en blanco
+		for i < len(runes) && unicode.IsSpace(runes[i]) {
+			i++
+		}
+
+		// Fin de línea o inicio de comentario
+		if i >= len(runes) || runes[i] == '#' {
+			cmd.Raw = strings.TrimSpace(string(runes[:i]))
+			return cmd, nil
+		}
+
+		start := i
+
+		// El primer token es el nombre del comando
+		if cmd.Name == "" {
+			for i < len(runes) && !unicode.IsSpace(runes[i]) {
+				i++
+			}
+			cmd.Name = strings.ToLower(string(runes[start:i]))
+			cmd.Column = start + 1
+			if strings.HasPrefix(cmd.Name, "-") {
+				return cmd, fmt.Errorf("se esperaba un comando en la columna %d y se encontró el parámetro %s", start+1, cmd.Name)
+			}
+			continue
+		}
+
+		// Lo que no inicia con guion es un argumento posicional; cada comando decide si lo acepta
+		if runes[i] != '-' {
+			value, next, err := lexValue(runes, i)
+			if err != nil {
+				return cmd, err
+			}
+			cmd.Args = append(cmd.Args, Structs.Parameter{Value: value, Column: start + 1, HasValue: true})
+			i = next
+			continue
+		}
+
+		param, next, err := lexParameter(runes, i)
+		if err != nil {
+			return cmd, err
+		}
+		cmd.Params = append(cmd.Params, param)
+		i = next
+	}
+}
+
+// lexParameter lee un parámetro -nombre[=valor] a partir de la posición del guion
+func lexParameter(runes []rune, i int) (Structs.Parameter, int, error) {
+	param := Structs.Parameter{Column: i + 1}
+	i++ // Saltar el guion
+
+	// Leer el nombre hasta "=", espacio o fin de línea
+	nameStart := i
+	for i < len(runes) && runes[i] != '=' && !unicode.IsSpace(runes[i]) {
+		if runes[i] == '"' {
+			return param, i, fmt.Errorf("comilla inesperada en el nombre del parámetro en la columna %d", i+1)
+		}
+		i++
+	}
+	param.Name = strings.ToLower(string(runes[nameStart:i]))
+	if param.Name == "" {
+		return param, i, fmt.Errorf("parámetro sin nombre en la columna %d", param.Column)
+	}
+
+	// Parámetro sin valor (por ejemplo -r)
+	if i >= len(runes) || runes[i] != '=' {
+		return param, i, nil
+	}
+	i++ // Saltar el "="
+	param.HasValue = true
+
+	value, next, err := lexValue(runes, i)
+	if err != nil {
+		return param, next, err
+	}
+	param.Value = value
+	return param, next, nil
+}
+
+// lexValue lee el valor de un parámetro hasta el siguiente espacio fuera de comillas
+func lexValue(runes []rune, i int) (string, int, error) {
+	var value strings.Builder
+	inQuotes := false
+	quoteColumn := 0
+
+	for i < len(runes) {
+		r := runes[i]
+
+		// Comilla escapada: se agrega tal cual
+		if r == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || (inQuotes && runes[i+1] == '\\')) {
+			value.WriteRune(runes[i+1])
+			i += 2
+			continue
+		}
+
+		if r == '"' {
+			inQuotes = !inQuotes
+			if inQuotes {
+				quoteColumn = i + 1
+			}
+			i++
+			continue
+		}
+
+		if !inQuotes && unicode.IsSpace(r) {
+			break
+		}
+
+		value.WriteRune(r)
+		i++
+	}
+
+	if inQuotes {
+		return "", i, fmt.Errorf("comillas sin cerrar a partir de la columna %d", quoteColumn)
+	}
+	return value.String(), i, nil
+}
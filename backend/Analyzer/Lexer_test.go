@@ -0,0 +1,139 @@
+package Analyzer
+
+import (
+	"backend/Structs"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLex(t *testing.T) {
+	// param arma un parámetro con valor; flag, uno sin valor
+	param := func(name string, value string, column int) Structs.Parameter {
+		return Structs.Parameter{Name: name, Value: value, HasValue: true, Column: column}
+	}
+	flag := func(name string, column int) Structs.Parameter {
+		return Structs.Parameter{Name: name, Column: column}
+	}
+
+	tests := []struct {
+		name   string
+		input  string
+		want   Structs.Command
+		errMsg string // Si no está vacío, parte del error esperado
+	}{
+		{
+			name:  "comando y parámetros",
+			input: "mkdisk -size=5 -unit=M",
+			want:  Structs.Command{Name: "mkdisk", Column: 1, Params: []Structs.Parameter{param("size", "5", 8), param("unit", "M", 16)}, Raw: "mkdisk -size=5 -unit=M"},
+		},
+		{
+			name:  "nombres en minúsculas y espacios extra",
+			input: "  MKDISK   -Size=5\t",
+			want:  Structs.Command{Name: "mkdisk", Column: 3, Params: []Structs.Parameter{param("size", "5", 12)}, Raw: "MKDISK   -Size=5"},
+		},
+		{
+			name:  "valor entre comillas con espacios",
+			input: `mkdisk -path="/home/mis discos/a.mia"`,
+			want:  Structs.Command{Name: "mkdisk", Column: 1, Params: []Structs.Parameter{param("path", "/home/mis discos/a.mia", 8)}, Raw: `mkdisk -path="/home/mis discos/a.mia"`},
+		},
+		{
+			name:  "comillas en medio del valor",
+			input: `mkfile -path=/a/"b c"/d.txt`,
+			want:  Structs.Command{Name: "mkfile", Column: 1, Params: []Structs.Parameter{param("path", "/a/b c/d.txt", 8)}, Raw: `mkfile -path=/a/"b c"/d.txt`},
+		},
+		{
+			name:  "comilla escapada",
+			input: `mkfile -cont="dijo \"hola\""`,
+			want:  Structs.Command{Name: "mkfile", Column: 1, Params: []Structs.Parameter{param("cont", `dijo "hola"`, 8)}, Raw: `mkfile -cont="dijo \"hola\""`},
+		},
+		{
+			name:  "comilla escapada fuera de comillas",
+			input: `mkfile -cont=a\"b`,
+			want:  Structs.Command{Name: "mkfile", Column: 1, Params: []Structs.Parameter{param("cont", `a"b`, 8)}, Raw: `mkfile -cont=a\"b`},
+		},
+		{
+			name:  "barra escapada dentro de comillas",
+			input: `mkfile -cont="c:\\temp"`,
+			want:  Structs.Command{Name: "mkfile", Column: 1, Params: []Structs.Parameter{param("cont", `c:\temp`, 8)}, Raw: `mkfile -cont="c:\\temp"`},
+		},
+		{
+			name:  "barra fuera de comillas se conserva",
+			input: `mkfile -cont=c:\temp`,
+			want:  Structs.Command{Name: "mkfile", Column: 1, Params: []Structs.Parameter{param("cont", `c:\temp`, 8)}, Raw: `mkfile -cont=c:\temp`},
+		},
+		{
+			name:  "igual dentro del valor",
+			input: "mkfile -cont=a=b",
+			want:  Structs.Command{Name: "mkfile", Column: 1, Params: []Structs.Parameter{param("cont", "a=b", 8)}, Raw: "mkfile -cont=a=b"},
+		},
+		{
+			name:  "valores vacíos",
+			input: `login -user= -pass=""`,
+			want:  Structs.Command{Name: "login", Column: 1, Params: []Structs.Parameter{param("user", "", 7), param("pass", "", 14)}, Raw: `login -user= -pass=""`},
+		},
+		{
+			name:  "parámetro sin valor",
+			input: "mkdir -p -path=/a/b",
+			want:  Structs.Command{Name: "mkdir", Column: 1, Params: []Structs.Parameter{flag("p", 7), param("path", "/a/b", 10)}, Raw: "mkdir -p -path=/a/b"},
+		},
+		{
+			name:  "argumento posicional",
+			input: `help "mkdisk"`,
+			want:  Structs.Command{Name: "help", Column: 1, Args: []Structs.Parameter{{Value: "mkdisk", HasValue: true, Column: 6}}, Raw: `help "mkdisk"`},
+		},
+		{
+			name:  "comentario al final",
+			input: "mount -path=/a.mia -name=p1 # montar p1",
+			want:  Structs.Command{Name: "mount", Column: 1, Params: []Structs.Parameter{param("path", "/a.mia", 7), param("name", "p1", 20)}, Raw: "mount -path=/a.mia -name=p1"},
+		},
+		{
+			name:  "numeral dentro de comillas o de un valor",
+			input: `mkfile -cont="a # b" -path=/c#d`,
+			want:  Structs.Command{Name: "mkfile", Column: 1, Params: []Structs.Parameter{param("cont", "a # b", 8), param("path", "/c#d", 22)}, Raw: `mkfile -cont="a # b" -path=/c#d`},
+		},
+		{
+			name:  "línea vacía o solo comentario",
+			input: "   # nada",
+			want:  Structs.Command{},
+		},
+		{
+			name:   "comillas sin cerrar",
+			input:  `mkdisk -path="/a b`,
+			errMsg: "comillas sin cerrar a partir de la columna 14",
+		},
+		{
+			name:   "comilla en el nombre del parámetro",
+			input:  `mkdisk -pa"th=/a`,
+			errMsg: "comilla inesperada en el nombre del parámetro en la columna 11",
+		},
+		{
+			name:   "parámetro sin nombre",
+			input:  "mkdisk -=5",
+			errMsg: "parámetro sin nombre en la columna 8",
+		},
+		{
+			name:   "parámetro en lugar del comando",
+			input:  "-size=5 mkdisk",
+			errMsg: "se esperaba un comando en la columna 1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmd, err := Lex(test.input)
+			if test.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), test.errMsg) {
+					t.Fatalf("Lex(%q): error %v, se esperaba %q", test.input, err, test.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Lex(%q): %v", test.input, err)
+			}
+			if !reflect.DeepEqual(cmd, test.want) {
+				t.Errorf("Lex(%q) =\n%+v\nse esperaba\n%+v", test.input, cmd, test.want)
+			}
+		})
+	}
+}
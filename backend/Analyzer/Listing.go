@@ -0,0 +1,122 @@
+package Analyzer
+
+import (
+	"backend/DiskManagement"
+	"backend/Structs"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// ListMounted devuelve las particiones montadas para el frontend; espera a que termine el script en curso
+func ListMounted() []DiskManagement.MountInfo {
+	runMutex.Lock()
+	defer runMutex.Unlock()
+	return DiskManagement.ListMounted()
+}
+
+// ListDisks devuelve los discos .mia de dir para el frontend; espera a que termine el script en curso
+func ListDisks(dir string) ([]DiskManagement.DiskInfo, error) {
+	runMutex.Lock()
+	defer runMutex.Unlock()
+	return DiskManagement.ListDisks(dir)
+}
+
+// toJSON da el valor como JSON legible, que es la salida de los comandos con -json
+func toJSON(value interface{}) (string, error) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("no se pudo convertir a JSON: %v", err)
+	}
+	return string(data), nil
+}
+
+// yesNo traduce un booleano para las tablas de texto
+func yesNo(value bool) string {
+	if value {
+		return "sí"
+	}
+	return "no"
+}
+
+func fn_mounted(cmd Structs.Command) (string, error) {
+	list := DiskManagement.ListMounted()
+	if cmd.Has("json") {
+		return toJSON(list)
+	}
+	if len(list) == 0 {
+		return "MOUNTED: No hay particiones montadas", nil
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "MOUNTED: %d partición(es) montada(s)\n", len(list))
+	table := tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tNOMBRE\tTIPO\tINICIO\tTAMAÑO\tSISTEMA\tSESIÓN\tDISCO")
+	for _, mount := range list {
+		filesystem := mount.Filesystem
+		if filesystem == "" {
+			filesystem = "sin formato"
+		}
+		if mount.Error != "" {
+			filesystem = "error: " + mount.Error
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%d\t%d\t%s\t%s\t%s\n", mount.ID, mount.Name, mount.Type, mount.Start, mount.Size, filesystem, yesNo(mount.LoggedIn), mount.Path)
+	}
+	table.Flush()
+	return strings.TrimRight(out.String(), "\n"), nil
+}
+
+// En la simulación se listan los montajes del modelo, que incluyen los de las líneas anteriores
+func dry_mounted(s *dryRunState, cmd Structs.Command) (string, error) {
+	var ids []string
+	for _, partitions := range s.mounts {
+		for _, mount := range partitions {
+			ids = append(ids, mount.ID)
+		}
+	}
+	return fmt.Sprintf("%smounted listaría %d partición(es) montada(s): %s", dryRunPrefix, len(ids), strings.Join(ids, ", ")), nil
+}
+
+func fn_lsdisk(cmd Structs.Command) (string, error) {
+	dir := cmd.Value("dir")
+	disks, err := DiskManagement.ListDisks(dir)
+	if err != nil {
+		return "", err
+	}
+	if cmd.Has("json") {
+		return toJSON(disks)
+	}
+	if len(disks) == 0 {
+		return fmt.Sprintf("LSDISK: No hay discos .mia en %s", dir), nil
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "LSDISK: %d disco(s) en %s\n", len(disks), dir)
+	for _, disk := range disks {
+		if disk.Error != "" {
+			fmt.Fprintf(&out, "\n%s: %s\n", disk.Path, disk.Error)
+			continue
+		}
+		fmt.Fprintf(&out, "\n%s: %d bytes, %d bits, tabla %s, firma %d, creado %s, ajuste %s\n", disk.Path, disk.Size, disk.Format, disk.Table, disk.Signature, disk.CreationDate, disk.Fit)
+		if disk.Warning != "" {
+			fmt.Fprintf(&out, "  Advertencia: %s\n", disk.Warning)
+		}
+		if len(disk.Partitions) == 0 {
+			out.WriteString("  Sin particiones\n")
+			continue
+		}
+		table := tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "  NOMBRE\tTIPO\tAJUSTE\tESTADO\tID\tINICIO\tTAMAÑO")
+		for _, partition := range disk.Partitions {
+			fmt.Fprintf(table, "  %s\t%s\t%s\t%s\t%s\t%d\t%d\n", partition.Name, partition.Type, partition.Fit, partition.Status, partition.ID, partition.Start, partition.Size)
+		}
+		table.Flush()
+	}
+	return strings.TrimRight(out.String(), "\n"), nil
+}
+
+// lsdisk solo lee los discos, así que en la simulación se ejecuta tal cual
+func dry_lsdisk(s *dryRunState, cmd Structs.Command) (string, error) {
+	return fn_lsdisk(cmd)
+}
@@ -0,0 +1,389 @@
+package Analyzer
+
+import (
+	"backend/DiskManagement"
+	"backend/Structs"
+	"backend/User"
+	"backend/Utilities"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Nombre con el que se reportan las líneas que no vienen de un archivo (por ejemplo, el cuerpo de /analyze)
+const InputName = "entrada"
+
+// Estados posibles de un resultado
+const (
+	StatusOK      = "ok"
+	StatusError   = "error"
+	StatusComment = "comment"
+)
+
+// Mensaje del resultado que se agrega cuando el cliente cancela el script
+const cancelledMessage = "ejecución cancelada por el cliente"
+
+// Los scripts modifican estado global (montajes, sesión, progreso), así que se ejecutan de uno en uno
+var runMutex sync.Mutex
+
+// Result es el resultado de una línea de un script
+type Result struct {
+	File       string   `json:"file"`                 // Script de donde viene la línea
+	Line       int      `json:"line"`                 // Número de línea (1-based) dentro del script
+	Command    string   `json:"command"`              // Texto original de la línea
+	Status     string   `json:"status"`               // StatusOK, StatusError o StatusComment
+	Message    string   `json:"message"`              // Salida del comando o texto del error
+	Warnings   []string `json:"warnings,omitempty"`   // Advertencias del modo no estricto
+	Artifacts  []string `json:"artifacts,omitempty"`  // Archivos generados (discos, reportes)
+	ElapsedMs  float64  `json:"elapsedMs"`            // Tiempo de ejecución en milisegundos
+	RolledBack bool     `json:"rolledBack,omitempty"` // En modo atómico: la línea funcionó pero sus cambios se deshicieron
+}
+
+// String devuelve el resultado con su ubicación ("archivo:línea: mensaje"), como lo muestran la
+// terminal y la salida de execute
+func (res Result) String() string {
+	switch res.Status {
+	case StatusComment:
+		return res.Command
+	case StatusError:
+		return fmt.Sprintf("%s:%d: Error: %s", res.File, res.Line, res.Message)
+	default:
+		return fmt.Sprintf("%s:%d: %s", res.File, res.Line, withWarnings(res.Message, res.Warnings))
+	}
+}
+
+// Strings convierte los resultados al formato de texto de siempre: el comentario, la salida del
+// comando o "Error: mensaje", sin la ubicación ni las advertencias, que van en los campos de Result
+func Strings(results []Result) []string {
+	lines := make([]string, 0, len(results))
+	for _, res := range results {
+		switch res.Status {
+		case StatusComment:
+			lines = append(lines, res.Command)
+		case StatusError:
+			lines = append(lines, "Error: "+res.Message)
+		default:
+			lines = append(lines, res.Message)
+		}
+	}
+	return lines
+}
+
+// scriptRunner ejecuta scripts línea por línea y acumula los resultados.
+// Guarda la pila de archivos en ejecución para soportar execute anidado y detectar ciclos.
+type scriptRunner struct {
+	opts    Options
+	stack   []string // Rutas absolutas de los scripts que se están ejecutando
+	results []Result
+	errors  int
+	shadow  *dryRunState // Modelo de la simulación; nil si los comandos se ejecutan de verdad
+}
+
+// RunScript ejecuta un script completo y devuelve un resultado por línea
+func RunScript(source string, name string, opts Options) []Result {
+	runMutex.Lock()
+	defer runMutex.Unlock()
+
+	// El progreso de las operaciones largas se envía a quien ejecuta este script
+	Utilities.SetProgressHandler(opts.OnProgress)
+	defer Utilities.SetProgressHandler(nil)
+
+	runner := &scriptRunner{opts: opts}
+	if opts.DryRun {
+		runner.shadow = newDryRunState()
+	}
+	if !opts.Atomic || opts.DryRun {
+		runner.run(source, name, "")
+		return runner.results
+	}
+
+	// En modo atómico cualquier error deshace todo el script
+	if _, err := runner.atomically(func() { runner.run(source, name, "") }); err != nil {
+		runner.results = append(runner.results, Result{File: name, Status: StatusError, Message: err.Error()})
+	}
+	return runner.results
+}
+
+// atomically ejecuta run dentro de una transacción. Si alguna línea falla se deshacen los
+// cambios en los discos y se restauran las tablas de montajes y la sesión.
+// Si ya hay una transacción en curso, run forma parte de ella. Devuelve true si se deshizo.
+func (r *scriptRunner) atomically(run func()) (bool, error) {
+	if Utilities.InTransaction() {
+		run()
+		return false, nil
+	}
+
+	if err := Utilities.BeginTransaction(); err != nil {
+		return false, err
+	}
+	mounts, ids := DiskManagement.SnapshotMounts(), DiskManagement.SnapshotIDAssignments()
+	session, user := User.CurrentLoggedPartitionID, User.CurrentUser
+	first := len(r.results)
+	errorsBefore := r.errors
+
+	run()
+
+	if r.errors == errorsBefore {
+		return false, Utilities.CommitTransaction()
+	}
+
+	err := Utilities.RollbackTransaction()
+	DiskManagement.RestoreMounts(mounts)
+	DiskManagement.RestoreIDAssignments(ids)
+	User.CurrentLoggedPartitionID, User.CurrentUser = session, user
+	for i := first; i < len(r.results); i++ {
+		if r.results[i].Status == StatusOK {
+			r.results[i].RolledBack = true
+		}
+	}
+	return true, err
+}
+
+// RunFile ejecuta un script del host; los execute con rutas relativas se resuelven respecto a su carpeta
+func RunFile(path string, opts Options) ([]Result, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("ruta inválida %s: %v", path, err)
+	}
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo leer el script %s: %v", path, err)
+	}
+
+	runMutex.Lock()
+	defer runMutex.Unlock()
+
+	// Igual que en RunScript, el progreso se envía a quien ejecuta el archivo
+	Utilities.SetProgressHandler(opts.OnProgress)
+	defer Utilities.SetProgressHandler(nil)
+
+	runner := &scriptRunner{opts: opts, stack: []string{absPath}}
+	if opts.DryRun {
+		runner.shadow = newDryRunState()
+	}
+	run := func() { runner.run(string(content), filepath.Base(absPath), filepath.Dir(absPath)) }
+	if !opts.Atomic || opts.DryRun {
+		run()
+		return runner.results, nil
+	}
+	if _, err := runner.atomically(run); err != nil {
+		return runner.results, err
+	}
+	return runner.results, nil
+}
+
+// Cancelled indica si el canal de cancelación ya se cerró
+func (opts Options) Cancelled() bool {
+	if opts.Cancel == nil {
+		return false
+	}
+	select {
+	case <-opts.Cancel:
+		return true
+	default:
+		return false
+	}
+}
+
+// add agrega un resultado y lo notifica a OnResult si está definido
+func (r *scriptRunner) add(res Result) {
+	r.results = append(r.results, res)
+	if r.opts.OnResult != nil {
+		r.opts.OnResult(res)
+	}
+}
+
+// run ejecuta cada línea del script; devuelve false si se detuvo por un error
+func (r *scriptRunner) run(source string, name string, dir string) bool {
+	lines := strings.Split(source, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		// Ignorar líneas vacías
+		if trimmed == "" {
+			continue
+		}
+
+		// Si el cliente canceló, la línea actual se reporta como error y el script se detiene
+		if r.opts.Cancelled() {
+			r.errors++
+			r.add(Result{File: name, Line: i + 1, Command: trimmed, Status: StatusError, Message: cancelledMessage})
+			return false
+		}
+
+		// Los comentarios se agregan tal cual a los resultados
+		if strings.HasPrefix(trimmed, "#") {
+			r.add(Result{File: name, Line: i + 1, Command: trimmed, Status: StatusComment, Message: trimmed})
+			continue
+		}
+
+		res := Result{File: name, Line: i + 1, Command: trimmed}
+		if !r.runLine(line, res, dir) && r.opts.StopOnError {
+			return false
+		}
+	}
+	return true
+}
+
+// runLine ejecuta una línea y agrega su resultado; devuelve false si terminó en error
+func (r *scriptRunner) runLine(line string, res Result, dir string) bool {
+	start := time.Now()
+	spec, cmd, warnings, err := prepare(line, r.opts)
+	if err != nil {
+		return r.finish(res, start, "", err)
+	}
+	res.Warnings = warnings
+
+	// execute se resuelve aquí para compartir la pila de archivos y los resultados;
+	// su resultado se agrega después de las líneas del script que ejecutó
+	if spec.Name == "execute" {
+		message, err := r.execute(cmd, dir)
+		return r.finish(res, start, message, err)
+	}
+
+	// En la simulación nada se ejecuta de verdad ni genera archivos
+	if r.shadow != nil {
+		message, err := spec.DryRun(r.shadow, cmd)
+		return r.finish(res, start, message, err)
+	}
+
+	message, err := runCommand(spec, cmd, fmt.Sprintf("%s:%d", res.File, res.Line))
+	if err == nil && spec.Artifacts != nil {
+		res.Artifacts = spec.Artifacts(cmd)
+	}
+	return r.finish(res, start, message, err)
+}
+
+// finish completa el resultado de una línea y lo agrega; devuelve false si hubo error
+func (r *scriptRunner) finish(res Result, start time.Time, message string, err error) bool {
+	res.ElapsedMs = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		r.errors++
+		res.Status = StatusError
+		res.Message = err.Error()
+		res.Artifacts = nil
+		r.add(res)
+		return false
+	}
+	res.Status = StatusOK
+	res.Message = message
+	r.add(res)
+	return true
+}
+
+// execute lee un script del host y lo ejecuta con la misma pila de archivos
+func (r *scriptRunner) execute(cmd Structs.Command, dir string) (string, error) {
+	path := cmd.Value("path")
+
+	// Las rutas relativas se resuelven respecto al script que hace el execute
+	if !filepath.IsAbs(path) && dir != "" {
+		path = filepath.Join(dir, path)
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("ruta inválida %s: %v", path, err)
+	}
+
+	// Detectar ciclos: el archivo ya se está ejecutando más arriba en la pila
+	for i, running := range r.stack {
+		if running == absPath {
+			cycle := append(append([]string{}, r.stack[i:]...), absPath)
+			return "", fmt.Errorf("ciclo de execute detectado: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		return "", fmt.Errorf("no se pudo leer el script %s: %v", path, err)
+	}
+
+	// -onerror cambia el comportamiento solo para este script y los que ejecute
+	previous := r.opts
+	if onError, ok := cmd.Get("onerror"); ok {
+		r.opts.StopOnError = onError == "stop"
+	}
+	errorsBefore := r.errors
+
+	// -dryrun simula el script (y los que ejecute) sin tocar los discos
+	if cmd.Has("dryrun") && r.shadow == nil {
+		r.shadow = newDryRunState()
+		defer func() { r.shadow = nil }()
+	}
+
+	r.stack = append(r.stack, absPath)
+	completed := true
+	runNested := func() {
+		completed = r.run(string(content), filepath.Base(absPath), filepath.Dir(absPath))
+	}
+
+	// -atomic deshace los cambios del script si alguna de sus líneas falla
+	rolledBack := false
+	if cmd.Has("atomic") && r.shadow == nil {
+		rolledBack, err = r.atomically(runNested)
+	} else {
+		runNested()
+	}
+	r.stack = r.stack[:len(r.stack)-1]
+	r.opts = previous
+
+	failed := r.errors - errorsBefore
+	if err != nil {
+		return "", fmt.Errorf("el script %s falló y no se pudieron deshacer sus cambios: %v", path, err)
+	}
+	if r.opts.Cancelled() {
+		return "", fmt.Errorf("el script %s fue cancelado", path)
+	}
+	if rolledBack {
+		return "", fmt.Errorf("el script %s terminó con %d error(es) y se deshicieron sus cambios", path, failed)
+	}
+	if !completed {
+		return "", fmt.Errorf("el script %s se detuvo en el primer error", path)
+	}
+	return fmt.Sprintf("EXECUTE: Script %s ejecutado con %d error(es)", path, failed), nil
+}
+
+// executeLine ejecuta execute cuando llega como una sola línea (sin un script que lo contenga), con
+// las opciones indicadas y el mismo bloqueo que RunScript
+func executeLine(cmd Structs.Command, opts Options) (string, error) {
+	runMutex.Lock()
+	defer runMutex.Unlock()
+
+	Utilities.SetProgressHandler(opts.OnProgress)
+	defer Utilities.SetProgressHandler(nil)
+
+	runner := &scriptRunner{opts: opts}
+	if opts.DryRun {
+		runner.shadow = newDryRunState()
+	}
+	var message string
+	var err error
+	run := func() { message, err = runner.execute(cmd, "") }
+	if opts.Atomic && !opts.DryRun {
+		rolledBack, rollbackErr := runner.atomically(run)
+		switch {
+		case rollbackErr != nil:
+			err = rollbackErr
+		case rolledBack && err == nil:
+			err = fmt.Errorf("el script %s terminó con %d error(es) y se deshicieron sus cambios", cmd.Value("path"), runner.errors)
+		}
+	} else {
+		run()
+	}
+
+	var lines []string
+	for _, res := range runner.results {
+		lines = append(lines, res.String())
+	}
+	if message != "" {
+		lines = append(lines, message)
+	}
+	return strings.Join(lines, "\n"), err
+}
+
+// fn_execute ejecuta execute con las opciones por defecto; AnalyzerWithOptions usa executeLine con las suyas
+func fn_execute(cmd Structs.Command) (string, error) {
+	return executeLine(cmd, DefaultOptions)
+}
@@ -0,0 +1,146 @@
+package Analyzer
+
+import (
+	"backend/DiskManagement"
+	"backend/User"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readDir devuelve el contenido de cada archivo de la carpeta, por nombre
+func readDir(t *testing.T, dir string) map[string][]byte {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string][]byte)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[entry.Name()] = data
+	}
+	return files
+}
+
+// mountedIDs devuelve los IDs montados por nombre de partición
+func mountedIDs() map[string]string {
+	ids := make(map[string]string)
+	for _, partitions := range DiskManagement.GetMountedPartitions() {
+		for _, partition := range partitions {
+			ids[partition.Name] = partition.ID
+		}
+	}
+	return ids
+}
+
+// TestAtomicScriptRollback ejecuta en modo atómico un script que cambia discos, montajes y la sesión
+// y termina en un error, y revisa que todo quede igual que antes de ejecutarlo
+func TestAtomicScriptRollback(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv("MIA_DATA_DIR", dataDir)
+	mounts, ids := DiskManagement.SnapshotMounts(), DiskManagement.SnapshotIDAssignments()
+	t.Cleanup(func() {
+		DiskManagement.RestoreMounts(mounts)
+		DiskManagement.RestoreIDAssignments(ids)
+		User.CurrentLoggedPartitionID, User.CurrentUser = "", ""
+	})
+
+	// mkdisk pasa la ruta a minúsculas, así que los discos no pueden ir en t.TempDir()
+	disks, err := os.MkdirTemp("", "mia-atomico")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(disks) })
+	disk := func(name string) string { return filepath.Join(disks, name) }
+	setup := fmt.Sprintf(`mkdisk -size=1 -unit=M -path=%[1]s
+fdisk -size=200 -unit=k -path=%[1]s -name=p1
+fdisk -size=200 -unit=k -path=%[1]s -name=p2
+mount -path=%[1]s -name=p1
+mkdisk -size=1 -unit=M -path=%[2]s`, disk("a.mia"), disk("c.mia"))
+	for _, res := range RunScript(setup, "preparar.smia", Options{}) {
+		if res.Status == StatusError {
+			t.Fatalf("%s: %s", res.Command, res.Message)
+		}
+	}
+	p1 := mountedIDs()["p1"]
+	if _, err := Analyzer(fmt.Sprintf("mkfs -id=%s -type=full", p1)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Estado antes del script: bytes de los discos, tabla de montajes, mounts.json y sesión
+	diskBytes := readDir(t, disks)
+	mountsBefore := DiskManagement.SnapshotMounts()
+	mountsFile, err := os.ReadFile(filepath.Join(dataDir, "mounts.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	idsBefore := mountedIDs()
+
+	script := fmt.Sprintf(`fdisk -size=100 -unit=k -path=%[1]s -name=p3
+mount -path=%[1]s -name=p2
+login -user=root -pass=123 -id=%[3]s
+mkdir -path=/docs
+mkfile -path=/docs/a.txt -cont=hola
+mkdisk -size=1 -unit=M -path=%[2]s
+rmdisk -path=%[4]s -confirm
+comando_inexistente`, disk("a.mia"), disk("b.mia"), p1, disk("c.mia"))
+	results := RunScript(script, "atomico.smia", Options{Atomic: true})
+
+	var executed []string
+	for _, res := range results {
+		if res.Status == StatusOK {
+			executed = append(executed, res.Command)
+			if !res.RolledBack {
+				t.Errorf("%s no quedó marcado como deshecho", res.Command)
+			}
+		}
+	}
+	if len(executed) != 7 {
+		t.Fatalf("se esperaba que funcionaran las 7 primeras líneas, funcionaron %d:\n%s", len(executed), strings.Join(Strings(results), "\n"))
+	}
+
+	got := readDir(t, disks)
+	for name, data := range diskBytes {
+		if !bytes.Equal(got[name], data) {
+			t.Errorf("%s no volvió a su contenido original", name)
+		}
+	}
+	for name := range got {
+		if _, ok := diskBytes[name]; !ok {
+			t.Errorf("%s sigue existiendo después de deshacer", name)
+		}
+	}
+	if mounts := DiskManagement.SnapshotMounts(); !reflect.DeepEqual(mounts, mountsBefore) {
+		t.Errorf("tabla de montajes = %v, se esperaba %v", mounts, mountsBefore)
+	}
+	if got := mountedIDs(); !reflect.DeepEqual(got, idsBefore) {
+		t.Errorf("IDs montados = %v, se esperaba %v", got, idsBefore)
+	}
+	if data, err := os.ReadFile(filepath.Join(dataDir, "mounts.json")); err != nil || !bytes.Equal(data, mountsFile) {
+		t.Errorf("mounts.json = %s, se esperaba %s (%v)", data, mountsFile, err)
+	}
+	if User.CurrentLoggedPartitionID != "" || User.CurrentUser != "" {
+		t.Errorf("quedó una sesión abierta: %s en %s", User.CurrentUser, User.CurrentLoggedPartitionID)
+	}
+
+	// Los IDs que asignó el script deshecho se vuelven a asignar igual
+	if _, err := Analyzer(fmt.Sprintf("mount -path=%s -name=p2", disk("a.mia"))); err != nil {
+		t.Fatal(err)
+	}
+	for _, res := range results {
+		if strings.HasPrefix(res.Command, "mount ") && !strings.Contains(res.Message, mountedIDs()["p2"]) {
+			t.Errorf("el mount deshecho asignó otro ID que el de ahora (%s): %s", mountedIDs()["p2"], res.Message)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package Analyzer
+
+import (
+	"backend/Utilities"
+	"fmt"
+)
+
+// suggest devuelve el candidato más parecido a name, o "" si ninguno está lo bastante cerca
+func suggest(name string, candidates []string) string {
+	best := ""
+	bestDistance := 0
+	for _, candidate := range candidates {
+		distance := Utilities.Levenshtein(name, candidate)
+		if best == "" || distance < bestDistance {
+			best = candidate
+			bestDistance = distance
+		}
+	}
+
+	// Se aceptan hasta 2 ediciones, o 1 para nombres muy cortos
+	limit := 2
+	if len([]rune(name)) <= 3 {
+		limit = 1
+	}
+	if best == "" || bestDistance > limit {
+		return ""
+	}
+	return best
+}
+
+// didYouMean arma el texto de sugerencia, con el prefijo indicado (por ejemplo "-" para parámetros)
+func didYouMean(name string, candidates []string, prefix string) string {
+	if match := suggest(name, candidates); match != "" {
+		return fmt.Sprintf(", ¿quiso decir %s%s?", prefix, match)
+	}
+	return ""
+}
+
+// commandNames devuelve los nombres de todos los comandos registrados
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for _, spec := range commands {
+		names = append(names, spec.Name)
+	}
+	return names
+}
+
+// paramNames devuelve los nombres de los parámetros de un comando
+func (spec *CommandSpec) paramNames() []string {
+	names := make([]string, 0, len(spec.Params))
+	for _, def := range spec.Params {
+		names = append(names, def.Name)
+	}
+	return names
+}
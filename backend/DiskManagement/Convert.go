@@ -0,0 +1,255 @@
+package DiskManagement
+
+import (
+	"backend/Structs"
+	"backend/Utilities"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// FormatGrowth es una estructura que crece al pasar del formato de 32 al de 64 bits
+type FormatGrowth struct {
+	Position int64 // Inicio de la estructura en el disco de 32 bits
+	OldSize  int64
+	NewSize  int64
+}
+
+// FormatShift traduce posiciones de un disco de 32 bits a las que tienen después de convertirlo:
+// todo lo que está después de una estructura que crece se corre lo que esta creció
+type FormatShift []FormatGrowth
+
+// Grow agrega una estructura que crece
+func (shift *FormatShift) Grow(position int64, oldSize int64, newSize int64) {
+	*shift = append(*shift, FormatGrowth{Position: position, OldSize: oldSize, NewSize: newSize})
+}
+
+// Map devuelve la nueva posición de un byte que en el disco de 32 bits está en position y no
+// pertenece a ninguna estructura que crece (o es el inicio de una)
+func (shift FormatShift) Map(position int64) int64 {
+	mapped := position
+	for _, growth := range shift {
+		if growth.Position+growth.OldSize <= position {
+			mapped += growth.NewSize - growth.OldSize
+		}
+	}
+	return mapped
+}
+
+// Total devuelve cuánto crece el disco
+func (shift FormatShift) Total() int64 {
+	var total int64
+	for _, growth := range shift {
+		total += growth.NewSize - growth.OldSize
+	}
+	return total
+}
+
+// convertedPartition es una partición primaria o lógica con su posible superbloque, para convertirla
+type convertedPartition struct {
+	start      int64
+	superblock *Structs.Superblock // nil si no tiene EXT2
+}
+
+// ConvertDisk convierte en su lugar un disco de 32 bits al formato de 64 bits. El MBR, los EBR y los
+// superbloques crecen, así que lo que sigue a cada uno se corre y sus posiciones se recalculan; el
+// contenido de las particiones y sus sistemas de archivos no cambia. El disco nuevo se arma en un
+// archivo temporal que reemplaza al original solo si todo salió bien.
+func ConvertDisk(path string) (string, error) {
+	var logs string
+	logs += "======Start CONVERTDISK======\n"
+	logs += fmt.Sprintf("Path: %s\n", path)
+
+	if len(mountedPartitions[generateDiskID(path)]) > 0 {
+		errMsg := fmt.Sprintf("Error: El disco %s tiene particiones montadas; desmóntelas antes de convertirlo.", path)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+
+	source, err := os.Open(path)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error: Could not open file at path: %s", path)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	defer source.Close()
+
+	// Los discos GPT siempre son de 64 bits
+	format, err := DiskFormat(source)
+	if err == nil && format != Structs.Format32 {
+		errMsg := fmt.Sprintf("Error: El disco %s ya está en el formato de %d bits.", path, formatBits(format))
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	mbr, err := ReadMBR(source)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error: No se pudo leer el MBR: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	info, err := source.Stat()
+	if err != nil {
+		errMsg := fmt.Sprintf("Error: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+
+	// Reunir las estructuras que crecen: el MBR, cada EBR y cada superbloque
+	var shift FormatShift
+	shift.Grow(0, MBRSize(Structs.Format32), MBRSize(Structs.Format64))
+	var partitions []convertedPartition
+	chains := make(map[int][]ebrEntry)
+	addPartition := func(start int64) {
+		converted := convertedPartition{start: start}
+		if superblock, err := ReadSuperblock(source, start); err == nil && superblock.S_magic == 0xEF53 {
+			converted.superblock = &superblock
+			shift.Grow(start, SuperblockSize(Structs.Format32), SuperblockSize(Structs.Format64))
+		}
+		partitions = append(partitions, converted)
+	}
+	for i, partition := range mbr.Partitions {
+		if partition.Size == 0 {
+			continue
+		}
+		if partition.Type[0] != 'e' {
+			addPartition(partition.Start)
+			continue
+		}
+		chain, err := readEBRChain(source, partition)
+		if err != nil {
+			errMsg := fmt.Sprintf("Error: %v", err)
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+		chains[i] = chain
+		for _, entry := range chain {
+			shift.Grow(entry.Position, EBRSize(Structs.Format32), EBRSize(Structs.Format64))
+			if entry.EBR.PartSize > 0 {
+				addPartition(entry.EBR.PartStart)
+			}
+		}
+	}
+	sort.Slice(shift, func(i, j int) bool { return shift[i].Position < shift[j].Position })
+
+	// El temporal se crea con CreateFile para que una transacción lo trate como archivo nuevo
+	temp := path + ".convert.tmp"
+	os.Remove(temp)
+	if err := Utilities.CreateFile(temp); err != nil {
+		errMsg := fmt.Sprintf("Error: No se pudo crear el archivo temporal: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	target, err := os.OpenFile(temp, os.O_RDWR, 0644)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error: No se pudo crear el archivo temporal: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	defer os.Remove(temp)
+	defer target.Close()
+
+	// Copiar los bytes que están entre las estructuras, cada tramo corrido lo que corresponde
+	total := info.Size()
+	position := int64(0)
+	copyTo := func(end int64) error {
+		if end <= position {
+			return nil
+		}
+		writer := io.NewOffsetWriter(target, shift.Map(position))
+		if _, err := io.Copy(writer, io.NewSectionReader(source, position, end-position)); err != nil {
+			return err
+		}
+		Utilities.ReportProgress("convertdisk", end, total)
+		return nil
+	}
+	for _, growth := range shift {
+		if err := copyTo(growth.Position); err != nil {
+			errMsg := fmt.Sprintf("Error: No se pudo copiar el disco: %v", err)
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+		position = max(position, growth.Position+growth.OldSize)
+	}
+	if err := copyTo(total); err != nil {
+		errMsg := fmt.Sprintf("Error: No se pudo copiar el disco: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	if err := target.Truncate(total + shift.Total()); err != nil {
+		errMsg := fmt.Sprintf("Error: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+
+	// Escribir las estructuras en el formato nuevo; el MBR va primero porque marca el formato del archivo
+	oldEnd := func(partition Structs.Partition) int64 { return partition.Start + partition.Size }
+	converted := mbr
+	converted.Version = Structs.Format64
+	converted.MbrSize = mbr.MbrSize + shift.Total()
+	for i, partition := range mbr.Partitions {
+		if partition.Size == 0 {
+			continue
+		}
+		converted.Partitions[i].Start = shift.Map(partition.Start)
+		converted.Partitions[i].Size = shift.Map(oldEnd(partition)) - converted.Partitions[i].Start
+		logs += fmt.Sprintf("Partición %s: inicio %d -> %d, tamaño %d -> %d\n", strings.TrimRight(string(partition.Name[:]), "\x00"), partition.Start, converted.Partitions[i].Start, partition.Size, converted.Partitions[i].Size)
+	}
+	if err := WriteMBR(target, converted); err != nil {
+		errMsg := fmt.Sprintf("Error: No se pudo escribir el MBR: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+
+	for _, chain := range chains {
+		for _, entry := range chain {
+			ebr := entry.EBR
+			end := ebr.PartStart + ebr.PartSize
+			ebr.PartStart = shift.Map(ebr.PartStart)
+			if entry.EBR.PartSize > 0 {
+				ebr.PartSize = shift.Map(end) - ebr.PartStart
+			}
+			if ebr.PartNext != -1 {
+				ebr.PartNext = shift.Map(ebr.PartNext)
+			}
+			if err := WriteEBR(target, ebr, shift.Map(entry.Position)); err != nil {
+				errMsg := fmt.Sprintf("Error: No se pudo escribir el EBR de la posición %d: %v", entry.Position, err)
+				logs += errMsg + "\n"
+				return logs, fmt.Errorf(errMsg)
+			}
+		}
+	}
+
+	for _, partition := range partitions {
+		if partition.superblock == nil {
+			continue
+		}
+		superblock := *partition.superblock
+		superblock.S_bm_inode_start = shift.Map(superblock.S_bm_inode_start)
+		superblock.S_bm_block_start = shift.Map(superblock.S_bm_block_start)
+		superblock.S_inode_start = shift.Map(superblock.S_inode_start)
+		superblock.S_block_start = shift.Map(superblock.S_block_start)
+		if err := WriteSuperblock(target, superblock, shift.Map(partition.start)); err != nil {
+			errMsg := fmt.Sprintf("Error: No se pudo escribir el superbloque de la posición %d: %v", partition.start, err)
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+	}
+
+	if err := target.Close(); err != nil {
+		errMsg := fmt.Sprintf("Error: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	source.Close()
+	if err := Utilities.ReplaceFile(path, temp); err != nil {
+		errMsg := fmt.Sprintf("Error: No se pudo reemplazar el disco: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+
+	logs += fmt.Sprintf("Tamaño: %d -> %d bytes\n", mbr.MbrSize, converted.MbrSize)
+	logs += "======FIN CONVERTDISK======\n"
+	return logs + fmt.Sprintf("CONVERTDISK: Disco %s convertido al formato de 64 bits", path), nil
+}
@@ -0,0 +1,344 @@
+package DiskManagement
+
+import (
+	"backend/Structs"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Tamaños de las estructuras en los discos creados antes del formato de 64 bits
+const (
+	originalMBRSize        = 159
+	originalEBRSize        = 30
+	originalSuperblockSize = 94
+)
+
+// writeAt escribe cada estructura en su posición, en little endian como el resto del proyecto
+func writeAt(t *testing.T, file *os.File, objects map[int64]interface{}) {
+	t.Helper()
+	for position, object := range objects {
+		var buffer bytes.Buffer
+		if err := binary.Write(&buffer, binary.LittleEndian, object); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := file.WriteAt(buffer.Bytes(), position); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestOriginalLayoutSizes(t *testing.T) {
+	tests := []struct {
+		name string
+		got  int
+		want int
+	}{
+		{"MBR32", binary.Size(Structs.MBR32{}), originalMBRSize},
+		{"EBR32", binary.Size(Structs.EBR32{}), originalEBRSize},
+		{"Superblock32", binary.Size(Structs.Superblock32{}), originalSuperblockSize},
+	}
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Errorf("%s ocupa %d bytes, los discos originales usan %d", test.name, test.got, test.want)
+		}
+	}
+}
+
+func TestFormatShiftMap(t *testing.T) {
+	// Un MBR que crece 50 bytes y un EBR en 1000 que crece 20
+	var shift FormatShift
+	shift.Grow(0, 150, 200)
+	shift.Grow(1000, 30, 50)
+
+	tests := []struct {
+		name     string
+		position int64
+		want     int64
+	}{
+		{"inicio del MBR", 0, 0},
+		{"justo después del MBR", 150, 200},
+		{"entre el MBR y el EBR", 500, 550},
+		{"inicio del EBR", 1000, 1050},
+		{"dentro del EBR", 1010, 1060},
+		{"justo después del EBR", 1030, 1100},
+		{"final del disco", 5000, 5070},
+	}
+	for _, test := range tests {
+		if got := shift.Map(test.position); got != test.want {
+			t.Errorf("%s: Map(%d) = %d, se esperaba %d", test.name, test.position, got, test.want)
+		}
+	}
+	if got := shift.Total(); got != 70 {
+		t.Errorf("Total() = %d, se esperaba 70", got)
+	}
+}
+
+// diskLayout devuelve nombre, tipo y tamaño de cada partición del disco (las lógicas después de su
+// extendida). Con write escribe el nombre de cada una que no es extendida al inicio de sus datos;
+// sin write revisa que siga ahí.
+func diskLayout(t *testing.T, path string, write bool) []string {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	table, err := ReadTable(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var views []Structs.Partition
+	for _, partition := range table.Partitions {
+		if partition.Size == 0 {
+			continue
+		}
+		views = append(views, partition)
+		if partition.Type[0] == 'e' {
+			chain, err := readEBRChain(file, partition)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// El EBR inicial vacío no es una partición
+			for _, entry := range chain {
+				if entry.EBR.PartSize > 0 {
+					views = append(views, logicalView(entry.EBR))
+				}
+			}
+		}
+	}
+
+	var lines []string
+	for _, view := range views {
+		name := string(bytes.TrimRight(view.Name[:], "\x00"))
+		size := view.Size
+		if view.Type[0] == 'e' {
+			size = 0 // La extendida crece con sus EBR
+		} else if write {
+			file.WriteAt([]byte(name), view.Start)
+		} else {
+			marker := make([]byte, len(name))
+			file.ReadAt(marker, view.Start)
+			if string(marker) != name {
+				t.Errorf("la partición %s tiene %q al inicio de sus datos", name, marker)
+			}
+		}
+		lines = append(lines, fmt.Sprintf("%s %c %d", name, view.Type[0], size))
+	}
+	return lines
+}
+
+// TestConvertDiskRoundTrip crea un disco de 32 bits con mkdisk y fdisk, lo convierte y revisa que
+// quede con las mismas particiones y su contenido, y que no se pueda convertir dos veces
+func TestConvertDiskRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "disco.mia")
+	if _, err := Mkdisk(1, "ff", "m", path, "none", "32", "mbr", 0); err != nil {
+		t.Fatal(err)
+	}
+	partitions := []struct {
+		name  string
+		type_ string
+		size  int
+	}{
+		{"p1", "p", 100},
+		{"ext", "e", 400},
+		{"l1", "l", 100},
+		{"l2", "l", 100},
+		{"p2", "p", 200},
+	}
+	for _, partition := range partitions {
+		if _, err := Fdisk(partition.size, path, partition.name, "k", partition.type_, "w"); err != nil {
+			t.Fatalf("fdisk %s: %v", partition.name, err)
+		}
+	}
+
+	before := diskLayout(t, path, true)
+	if _, err := ConvertDisk(path); err != nil {
+		t.Fatal(err)
+	}
+	after := diskLayout(t, path, false)
+	if strings.Join(before, ", ") != strings.Join(after, ", ") {
+		t.Errorf("particiones después de convertir:\n%v\nantes:\n%v", after, before)
+	}
+
+	if _, err := ConvertDisk(path); err == nil {
+		t.Error("se esperaba un error al convertir un disco que ya es de 64 bits")
+	}
+}
+
+// TestConvertOriginalDisk arma byte a byte un disco con el formato original (una extendida con dos
+// lógicas, la segunda con EXT2, y una primaria) y revisa que ConvertDisk corra todo a su lugar
+func TestConvertOriginalDisk(t *testing.T) {
+	const (
+		diskSize      = 64 * 1024
+		extStart      = 1000
+		extSize       = 40000
+		firstEBR      = extStart
+		secondEBR     = 11030
+		logicalSize   = 10000
+		primaryStart  = extStart + extSize
+		primarySize   = 20000
+		secondStart   = secondEBR + originalEBRSize
+		bmInodeStart  = secondStart + originalSuperblockSize
+		bmBlockStart  = bmInodeStart + 16
+		inodeStart    = bmBlockStart + 48
+		blockStart    = inodeStart + 1000
+		firstContent  = "datos de la primera lógica"
+		blockContent  = "contenido del bloque"
+		primaryMarker = "primaria"
+	)
+
+	path := filepath.Join(t.TempDir(), "original.mia")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Truncate(diskSize); err != nil {
+		t.Fatal(err)
+	}
+
+	mbr := Structs.MBR32{MbrSize: diskSize, Signature: 1234}
+	copy(mbr.CreationDate[:], "2024-01-01")
+	mbr.Fit[0] = 'F'
+	mbr.Partitions[0] = Structs.Partition32{Status: [1]byte{'0'}, Type: [1]byte{'e'}, Fit: [1]byte{'W'}, Start: extStart, Size: extSize}
+	copy(mbr.Partitions[0].Name[:], "ext")
+	mbr.Partitions[1] = Structs.Partition32{Status: [1]byte{'0'}, Type: [1]byte{'p'}, Fit: [1]byte{'F'}, Start: primaryStart, Size: primarySize}
+	copy(mbr.Partitions[1].Name[:], "prim")
+
+	first := Structs.EBR32{PartMount: '0', PartFit: 'F', PartStart: firstEBR + originalEBRSize, PartSize: logicalSize, PartNext: secondEBR}
+	copy(first.PartName[:], "log1")
+	second := Structs.EBR32{PartMount: '0', PartFit: 'F', PartStart: secondStart, PartSize: logicalSize, PartNext: -1}
+	copy(second.PartName[:], "log2")
+
+	superblock := Structs.Superblock32{
+		S_filesystem_type: 2, S_inodes_count: 16, S_blocks_count: 48, S_free_blocks_count: 46, S_free_inodes_count: 14,
+		S_magic: 0xEF53, S_inode_size: 62, S_block_size: 64, S_fist_ino: 2, S_first_blo: 2,
+		S_bm_inode_start: bmInodeStart, S_bm_block_start: bmBlockStart, S_inode_start: inodeStart, S_block_start: blockStart,
+	}
+
+	writeAt(t, file, map[int64]interface{}{
+		0:                              mbr,
+		firstEBR:                       first,
+		firstEBR + originalEBRSize:     []byte(firstContent),
+		secondEBR:                      second,
+		secondStart:                    superblock,
+		bmInodeStart:                   []byte{1, 1},
+		blockStart:                     []byte(blockContent),
+		primaryStart:                   []byte(primaryMarker),
+		primaryStart + primarySize - 1: []byte{0xAB},
+	})
+	file.Close()
+
+	if _, err := ConvertDisk(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// Lo que hay antes de cada posición creció: el MBR, el primer EBR, el segundo EBR y el superbloque
+	mbrGrowth := MBRSize(Structs.Format64) - originalMBRSize
+	ebrGrowth := EBRSize(Structs.Format64) - originalEBRSize
+	superblockGrowth := SuperblockSize(Structs.Format64) - originalSuperblockSize
+	mapped := func(position int64, ebrs int64, superblocks int64) int64 {
+		return position + mbrGrowth + ebrs*ebrGrowth + superblocks*superblockGrowth
+	}
+
+	converted, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer converted.Close()
+
+	if format, err := DiskFormat(converted); err != nil || format != Structs.Format64 {
+		t.Fatalf("formato después de convertir = %d, %v; se esperaba %d", format, err, Structs.Format64)
+	}
+	table, err := ReadTable(converted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := table.MBR.MbrSize, mapped(diskSize, 2, 1); got != want {
+		t.Errorf("MbrSize = %d, se esperaba %d", got, want)
+	}
+	extended := table.Partitions[0]
+	if got, want := extended.Start, mapped(extStart, 0, 0); got != want {
+		t.Errorf("inicio de la extendida = %d, se esperaba %d", got, want)
+	}
+	if got, want := extended.Size, int64(extSize)+2*ebrGrowth+superblockGrowth; got != want {
+		t.Errorf("tamaño de la extendida = %d, se esperaba %d", got, want)
+	}
+	primary := table.Partitions[1]
+	if got, want := primary.Start, mapped(primaryStart, 2, 1); got != want {
+		t.Errorf("inicio de la primaria = %d, se esperaba %d", got, want)
+	}
+
+	chain, err := readEBRChain(converted, extended)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 2 {
+		t.Fatalf("la cadena tiene %d EBR, se esperaban 2", len(chain))
+	}
+	wantChain := []struct {
+		name     string
+		position int64
+		start    int64
+		size     int64
+	}{
+		{"log1", mapped(firstEBR, 0, 0), mapped(firstEBR+originalEBRSize, 1, 0), logicalSize},
+		{"log2", mapped(secondEBR, 1, 0), mapped(secondStart, 2, 0), logicalSize + superblockGrowth},
+	}
+	for i, want := range wantChain {
+		entry := chain[i]
+		if name := string(bytes.TrimRight(entry.EBR.PartName[:], "\x00")); name != want.name {
+			t.Errorf("EBR %d: nombre %q, se esperaba %q", i, name, want.name)
+		}
+		if entry.Position != want.position || entry.EBR.PartStart != want.start || entry.EBR.PartSize != want.size {
+			t.Errorf("EBR %s: posición %d, inicio %d, tamaño %d; se esperaba %d, %d, %d",
+				want.name, entry.Position, entry.EBR.PartStart, entry.EBR.PartSize, want.position, want.start, want.size)
+		}
+	}
+
+	read := func(position int64, length int) string {
+		buffer := make([]byte, length)
+		if _, err := converted.ReadAt(buffer, position); err != nil {
+			t.Fatal(err)
+		}
+		return string(buffer)
+	}
+	if got := read(chain[0].EBR.PartStart, len(firstContent)); got != firstContent {
+		t.Errorf("contenido de log1 = %q, se esperaba %q", got, firstContent)
+	}
+
+	converted2, err := ReadSuperblock(converted, chain[1].EBR.PartStart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if converted2.S_magic != 0xEF53 || converted2.S_inodes_count != 16 || converted2.S_blocks_count != 48 {
+		t.Errorf("superbloque de log2 dañado: %+v", converted2)
+	}
+	wantStarts := map[string][2]int64{
+		"S_bm_inode_start": {converted2.S_bm_inode_start, mapped(bmInodeStart, 2, 1)},
+		"S_bm_block_start": {converted2.S_bm_block_start, mapped(bmBlockStart, 2, 1)},
+		"S_inode_start":    {converted2.S_inode_start, mapped(inodeStart, 2, 1)},
+		"S_block_start":    {converted2.S_block_start, mapped(blockStart, 2, 1)},
+	}
+	for field, values := range wantStarts {
+		if values[0] != values[1] {
+			t.Errorf("%s = %d, se esperaba %d", field, values[0], values[1])
+		}
+	}
+	if got := read(converted2.S_bm_inode_start, 2); got != "\x01\x01" {
+		t.Errorf("bitmap de inodos = %q", got)
+	}
+	if got := read(converted2.S_block_start, len(blockContent)); got != blockContent {
+		t.Errorf("contenido del bloque = %q, se esperaba %q", got, blockContent)
+	}
+	if got := read(primary.Start, len(primaryMarker)); got != primaryMarker {
+		t.Errorf("contenido de la primaria = %q, se esperaba %q", got, primaryMarker)
+	}
+	if got := read(primary.Start+primary.Size-1, 1); got != "\xAB" {
+		t.Errorf("último byte de la primaria = %q", got)
+	}
+}
@@ -0,0 +1,529 @@
+package DiskManagement
+
+import (
+	"backend/Structs"
+	"backend/Utilities"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Exportación e importación de imágenes con un MBR estándar de DOS, para revisar los discos con
+// fdisk -l, sfdisk o parted del host. La tabla DOS cuenta en sectores de 512 bytes, así que al
+// exportar cada partición empieza en el sector donde empieza en el disco (o en el siguiente libre)
+// y su tamaño se redondea hacia arriba; lo que la tabla no guarda (nombres, ajustes, tamaños exactos
+// y el formato) va en el área de arranque para que importar la imagen devuelva el mismo disco.
+
+// dosSectors da los sectores que ocupan size bytes, redondeando hacia arriba
+func dosSectors(size int64) int64 {
+	return (size + Structs.DOSSectorSize - 1) / Structs.DOSSectorSize
+}
+
+// dosPlaced es una partición del disco ubicada en la imagen
+type dosPlaced struct {
+	partition Structs.Partition // En las lógicas, la vista de su EBR
+	number    int               // Número como en Linux: entrada + 1, o 5, 6, ... para las lógicas
+	lba       int64             // Primer sector de los datos en la imagen
+	sectors   int64
+	ebr       int64 // Sector del EBR de DOS de una lógica
+}
+
+// ExportMBR escribe en image una copia del disco con un MBR de DOS: entradas LBA y CHS, códigos de
+// tipo, la firma 0x55AA y las lógicas enlazadas con EBR de DOS. Las particiones se copian a su
+// sector y los superbloques se corren lo que se movió su partición. El disco no se modifica.
+func ExportMBR(path string, image string) (string, error) {
+	var logs string
+	logs += "======Start EXPORTMBR======\n"
+	logs += fmt.Sprintf("Path: %s\n", path)
+	logs += fmt.Sprintf("Image: %s\n", image)
+
+	source, err := os.Open(path)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error: Could not open file at path: %s", path)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	defer source.Close()
+
+	table, err := ReadTable(source)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error: No se pudo leer la tabla de particiones: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	format := table.MBR.Version
+
+	// Un disco con MBR conserva sus slots; en uno GPT las particiones ocupan las entradas en orden
+	var primaries []dosPlaced
+	for i, partition := range table.Partitions {
+		if partition.Size == 0 {
+			continue
+		}
+		slot := i
+		if table.GPT != nil {
+			slot = len(primaries)
+		}
+		if slot >= len(Structs.DOSMBR{}.Entries) {
+			errMsg := fmt.Sprintf("Error: El disco %s tiene más de 4 particiones en su GPT y el MBR de DOS solo admite 4 primarias.", path)
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+		primaries = append(primaries, dosPlaced{partition: partition, number: slot + 1})
+	}
+	sort.Slice(primaries, func(i, j int) bool { return primaries[i].partition.Start < primaries[j].partition.Start })
+
+	// Ubicar las particiones en orden de posición; el sector 0 es del MBR. El primer EBR de DOS va
+	// siempre al inicio de la extendida y cada lógica después de su EBR.
+	cursor := int64(1)
+	var logicals []dosPlaced
+	extendedLBA := int64(-1)
+	for i := range primaries {
+		placed := &primaries[i]
+		placed.lba = max(cursor, dosSectors(placed.partition.Start))
+		if placed.partition.Type[0] != 'e' {
+			placed.sectors = dosSectors(placed.partition.Size)
+			cursor = placed.lba + placed.sectors
+			continue
+		}
+
+		chain, err := readEBRChain(source, placed.partition)
+		if err != nil {
+			errMsg := fmt.Sprintf("Error: %v", err)
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+		inner := placed.lba
+		for _, entry := range chain {
+			if entry.EBR.PartSize == 0 {
+				continue
+			}
+			logical := dosPlaced{partition: logicalView(entry.EBR), number: LogicalNumber(len(logicals)), ebr: placed.lba}
+			if len(logicals) > 0 {
+				logical.ebr = max(inner, entry.Position/Structs.DOSSectorSize)
+			}
+			logical.lba = max(logical.ebr+1, dosSectors(entry.EBR.PartStart))
+			logical.sectors = dosSectors(entry.EBR.PartSize)
+			inner = logical.lba + logical.sectors
+			logicals = append(logicals, logical)
+		}
+		end := max(inner, placed.lba+1, dosSectors(placed.partition.Start+placed.partition.Size))
+		placed.sectors = end - placed.lba
+		cursor = end
+		extendedLBA = placed.lba
+	}
+	total := max(cursor, dosSectors(table.MBR.MbrSize))
+	if total > math.MaxUint32 {
+		errMsg := fmt.Sprintf("Error: El disco %s no cabe en un MBR de DOS, que con sectores de 512 bytes llega hasta 2 TiB.", path)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+
+	if _, err := os.Stat(image); err == nil {
+		errMsg := fmt.Sprintf("Error: Ya existe un archivo en %s; elija otra ruta para la imagen.", image)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	if err := Utilities.CreateFile(image); err != nil {
+		errMsg := fmt.Sprintf("Error al crear la imagen: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	target, err := Utilities.OpenFile(image)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error al abrir la imagen: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	defer target.Close()
+	if err := Utilities.TruncateFile(target, total*Structs.DOSSectorSize); err != nil {
+		errMsg := fmt.Sprintf("Error: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+
+	// Copiar los datos de las primarias y las lógicas
+	var copied, dataTotal int64
+	data := append([]dosPlaced(nil), logicals...)
+	for _, placed := range primaries {
+		if placed.partition.Type[0] != 'e' {
+			data = append(data, placed)
+		}
+	}
+	for _, placed := range data {
+		dataTotal += placed.partition.Size
+	}
+	for _, placed := range data {
+		start := placed.partition.Start
+		offset := placed.lba * Structs.DOSSectorSize
+		writer := io.NewOffsetWriter(target, offset)
+		if _, err := io.Copy(writer, io.NewSectionReader(source, start, placed.partition.Size)); err != nil {
+			errMsg := fmt.Sprintf("Error: No se pudo copiar la partición %s: %v", strings.TrimRight(string(placed.partition.Name[:]), "\x00"), err)
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+
+		// El superbloque guarda posiciones absolutas: se corren lo que se movió la partición
+		if superblock, err := readSuperblockFormat(source, format, start); err == nil && superblock.S_magic == 0xEF53 {
+			delta := offset - start
+			superblock.S_bm_inode_start += delta
+			superblock.S_bm_block_start += delta
+			superblock.S_inode_start += delta
+			superblock.S_block_start += delta
+			if err := writeSuperblockFormat(target, format, superblock, offset); err != nil {
+				errMsg := fmt.Sprintf("Error: No se pudo escribir el superbloque de la partición %s: %v", strings.TrimRight(string(placed.partition.Name[:]), "\x00"), err)
+				logs += errMsg + "\n"
+				return logs, fmt.Errorf(errMsg)
+			}
+		}
+		copied += placed.partition.Size
+		Utilities.ReportProgress("exportmbr", copied, dataTotal)
+	}
+
+	// MBR de DOS con los datos del disco en el área de arranque
+	dos := Structs.DOSMBR{DiskSignature: uint32(table.MBR.Signature), BootSignature: Structs.DOSBootSignature}
+	info := Structs.DOSBootInfo{Magic: Structs.DOSBootInfoMagic, Format: format, Fit: table.MBR.Fit, CreationDate: table.MBR.CreationDate}
+	describe := func(placed dosPlaced) {
+		logs += fmt.Sprintf("Partición %d (%s): inicio %d -> sector %d, %d sectores\n", placed.number, strings.TrimRight(string(placed.partition.Name[:]), "\x00"), placed.partition.Start, placed.lba, placed.sectors)
+		if placed.number > Structs.DOSBootMaxPartitions {
+			return
+		}
+		info.Names[placed.number-1] = placed.partition.Name
+		info.Fits[placed.number-1] = placed.partition.Fit[0]
+		info.Sizes[placed.number-1] = placed.partition.Size
+	}
+	for _, placed := range primaries {
+		type_ := Structs.DOSTypeLinux
+		if placed.partition.Type[0] == 'e' {
+			type_ = Structs.DOSTypeExtended
+		}
+		dos.Entries[placed.number-1] = Structs.NewDOSEntry(type_, 0, uint32(placed.lba), uint32(placed.sectors))
+		describe(placed)
+	}
+	for _, placed := range logicals {
+		describe(placed)
+	}
+	dos.SetBootInfo(info)
+	if err := Utilities.WriteObject(target, dos, 0); err != nil {
+		errMsg := fmt.Sprintf("Error: No se pudo escribir el MBR de DOS: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+
+	// EBR de DOS: la lógica relativa a su EBR y el siguiente EBR relativo al inicio de la extendida.
+	// Una extendida sin lógicas lleva un EBR vacío.
+	if extendedLBA >= 0 && len(logicals) == 0 {
+		ebr := Structs.DOSMBR{BootSignature: Structs.DOSBootSignature}
+		if err := Utilities.WriteObject(target, ebr, extendedLBA*Structs.DOSSectorSize); err != nil {
+			errMsg := fmt.Sprintf("Error: No se pudo escribir el EBR del sector %d: %v", extendedLBA, err)
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+	}
+	for k, logical := range logicals {
+		ebr := Structs.DOSMBR{BootSignature: Structs.DOSBootSignature}
+		ebr.Entries[0] = Structs.NewDOSEntry(Structs.DOSTypeLinux, uint32(logical.ebr), uint32(logical.lba-logical.ebr), uint32(logical.sectors))
+		if k+1 < len(logicals) {
+			next := logicals[k+1]
+			ebr.Entries[1] = Structs.NewDOSEntry(Structs.DOSTypeExtended, uint32(extendedLBA), uint32(next.ebr-extendedLBA), uint32(next.lba+next.sectors-next.ebr))
+		}
+		if err := Utilities.WriteObject(target, ebr, logical.ebr*Structs.DOSSectorSize); err != nil {
+			errMsg := fmt.Sprintf("Error: No se pudo escribir el EBR del sector %d: %v", logical.ebr, err)
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+	}
+
+	logs += fmt.Sprintf("Imagen: %d sectores de %d bytes\n", total, Structs.DOSSectorSize)
+	logs += "======FIN EXPORTMBR======\n"
+	return logs + fmt.Sprintf("EXPORTMBR: Disco %s exportado a %s con un MBR de DOS", path, image), nil
+}
+
+// DOSImagePartition es una partición leída de una imagen con MBR de DOS, en bytes
+type DOSImagePartition struct {
+	Slot  int  // Entrada del MBR; -1 en las lógicas
+	Type  byte // 'p', 'e' o 'l'
+	Name  string
+	Fit   byte
+	Start int64
+	Size  int64
+	EBR   int64 // Posición del EBR de DOS de una lógica
+	FSEnd int64 // Donde terminan las estructuras de su sistema de archivos; 0 si no tiene
+}
+
+// DOSImage es la tabla de una imagen con MBR de DOS junto con los datos del disco que dejó ExportMBR.
+// Si la imagen no viene de ExportMBR se usan el formato de 64 bits, nombres part1, part2, ... y los
+// ajustes por defecto.
+type DOSImage struct {
+	Size         int64
+	Format       int32
+	Fit          byte
+	CreationDate [10]byte
+	Signature    int32
+	Described    bool                // El área de arranque trae el DOSBootInfo de ExportMBR
+	Partitions   []DOSImagePartition // Primarias y extendida, en orden de entrada
+	Logicals     []DOSImagePartition // Lógicas en el orden de la cadena de EBR
+}
+
+// ReadDOSImage lee y valida el MBR de DOS de la imagen y la cadena de EBR de su extendida
+func ReadDOSImage(file *os.File) (*DOSImage, error) {
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	var dos Structs.DOSMBR
+	if err := Utilities.ReadObject(file, &dos, 0); err != nil {
+		return nil, fmt.Errorf("no se pudo leer el sector 0 de la imagen")
+	}
+	if dos.BootSignature != Structs.DOSBootSignature {
+		return nil, fmt.Errorf("la imagen no tiene un MBR de DOS: falta la firma 0x55AA")
+	}
+
+	boot, described := dos.BootInfo()
+	image := &DOSImage{Size: stat.Size(), Format: Structs.Format64, Fit: 'f', Signature: int32(dos.DiskSignature), Described: described}
+	copy(image.CreationDate[:], time.Now().Format("2006-01-02"))
+	if described {
+		if boot.Format == Structs.Format32 || boot.Format == Structs.Format64 {
+			image.Format = boot.Format
+		}
+		if strings.ContainsRune("bfw", rune(boot.Fit[0])) {
+			image.Fit = boot.Fit[0]
+		}
+		if boot.CreationDate != [10]byte{} {
+			image.CreationDate = boot.CreationDate
+		}
+	}
+	if image.Format == Structs.Format32 && image.Size > math.MaxInt32 {
+		return nil, fmt.Errorf("la imagen viene de un disco de 32 bits pero sus %d bytes ya no caben en ese formato", image.Size)
+	}
+
+	// Nombre, ajuste y tamaño exacto guardados al exportar. La extendida conserva el tamaño en
+	// sectores porque sus lógicas pueden haberse corrido hasta el final de su último sector.
+	describe := func(partition *DOSImagePartition, number int) {
+		partition.Name = fmt.Sprintf("part%d", number)
+		partition.Fit = 'w'
+		if !described || number > Structs.DOSBootMaxPartitions {
+			return
+		}
+		i := number - 1
+		if name := strings.TrimRight(string(boot.Names[i][:]), "\x00"); name != "" {
+			partition.Name = name
+		}
+		if strings.ContainsRune("bfw", rune(boot.Fits[i])) {
+			partition.Fit = boot.Fits[i]
+		}
+		if size := boot.Sizes[i]; partition.Type != 'e' && size > partition.Size-Structs.DOSSectorSize && size <= partition.Size {
+			partition.Size = size
+		}
+	}
+
+	extended := -1
+	for i, entry := range dos.Entries {
+		if entry.Type == Structs.DOSTypeGPT {
+			return nil, fmt.Errorf("la imagen tiene el MBR protector de una GPT; solo se importan tablas DOS")
+		}
+		if !entry.Used() {
+			continue
+		}
+		partition := DOSImagePartition{Slot: i, Type: 'p', Start: int64(entry.LBAStart) * Structs.DOSSectorSize, Size: int64(entry.Sectors) * Structs.DOSSectorSize}
+		if entry.IsExtended() {
+			if extended >= 0 {
+				return nil, fmt.Errorf("la imagen tiene más de una partición extendida")
+			}
+			partition.Type = 'e'
+			extended = len(image.Partitions)
+		}
+		if entry.LBAStart == 0 || partition.Start+partition.Size > image.Size {
+			return nil, fmt.Errorf("la entrada %d apunta fuera de la imagen (sectores %d a %d)", i+1, entry.LBAStart, int64(entry.LBAStart)+int64(entry.Sectors)-1)
+		}
+		describe(&partition, i+1)
+		image.Partitions = append(image.Partitions, partition)
+	}
+
+	sorted := append([]DOSImagePartition(nil), image.Partitions...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Start < sorted[i-1].Start+sorted[i-1].Size {
+			return nil, fmt.Errorf("las particiones %s y %s se superponen", sorted[i-1].Name, sorted[i].Name)
+		}
+	}
+
+	// Recorrer la cadena de EBR; cada EBR tiene que estar dentro de la extendida y después del anterior
+	if extended >= 0 {
+		ext := image.Partitions[extended]
+		extLBA := ext.Start / Structs.DOSSectorSize
+		extEnd := ext.Start + ext.Size
+		position := extLBA
+		for {
+			var ebr Structs.DOSMBR
+			if err := Utilities.ReadObject(file, &ebr, position*Structs.DOSSectorSize); err != nil {
+				return nil, fmt.Errorf("no se pudo leer el EBR del sector %d", position)
+			}
+			if ebr.BootSignature != Structs.DOSBootSignature {
+				return nil, fmt.Errorf("el EBR del sector %d no tiene la firma 0x55AA", position)
+			}
+			end := (position + 1) * Structs.DOSSectorSize
+			if entry := ebr.Entries[0]; entry.Used() {
+				logical := DOSImagePartition{Slot: -1, Type: 'l', Start: (position + int64(entry.LBAStart)) * Structs.DOSSectorSize, Size: int64(entry.Sectors) * Structs.DOSSectorSize, EBR: position * Structs.DOSSectorSize}
+				if entry.LBAStart == 0 || logical.Start+logical.Size > extEnd {
+					return nil, fmt.Errorf("la partición lógica del EBR del sector %d sale de la partición extendida", position)
+				}
+				end = logical.Start + logical.Size
+				describe(&logical, LogicalNumber(len(image.Logicals)))
+				image.Logicals = append(image.Logicals, logical)
+			}
+			next := ebr.Entries[1]
+			if !next.Used() || !next.IsExtended() {
+				break
+			}
+			nextPosition := extLBA + int64(next.LBAStart)
+			if nextPosition*Structs.DOSSectorSize < end || nextPosition*Structs.DOSSectorSize >= extEnd {
+				return nil, fmt.Errorf("la cadena de EBR no avanza dentro de la extendida en el sector %d", position)
+			}
+			position = nextPosition
+		}
+	}
+
+	// Los superbloques quedan en la misma posición al importar, así que se leen en el formato del disco
+	for _, partitions := range [][]DOSImagePartition{image.Partitions, image.Logicals} {
+		for i := range partitions {
+			if partitions[i].Type == 'e' {
+				continue
+			}
+			superblock, err := readSuperblockFormat(file, image.Format, partitions[i].Start)
+			if err == nil && superblock.S_magic == 0xEF53 {
+				partitions[i].FSEnd = superblock.S_block_start + int64(superblock.S_blocks_count)*int64(superblock.S_block_size)
+			}
+		}
+	}
+	return image, nil
+}
+
+// ImportMBR crea el disco path a partir de una imagen con MBR de DOS. Las particiones quedan en los
+// mismos bytes que en la imagen; el MBR y los EBR de DOS se reemplazan por los del disco.
+func ImportMBR(image string, path string) (string, error) {
+	var logs string
+	logs += "======Start IMPORTMBR======\n"
+	logs += fmt.Sprintf("Image: %s\n", image)
+	logs += fmt.Sprintf("Path: %s\n", path)
+
+	if _, err := os.Stat(path); err == nil {
+		errMsg := fmt.Sprintf("Error: Ya existe un disco en %s; elija otra ruta.", path)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	source, err := os.Open(image)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error: Could not open file at path: %s", image)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	defer source.Close()
+
+	dos, err := ReadDOSImage(source)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	if !dos.Described {
+		logs += "La imagen no la generó exportmbr: se usan el formato de 64 bits y nombres y ajustes por defecto\n"
+	}
+
+	if err := Utilities.CreateFile(path); err != nil {
+		errMsg := fmt.Sprintf("Error al crear el archivo: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	target, err := Utilities.OpenFile(path)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error al abrir el archivo: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	defer target.Close()
+	if _, err := io.Copy(target, io.NewSectionReader(source, 0, dos.Size)); err != nil {
+		errMsg := fmt.Sprintf("Error: No se pudo copiar la imagen: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	Utilities.ReportProgress("importmbr", dos.Size, dos.Size)
+
+	// Borrar los sectores de la tabla de DOS antes de escribir la del disco
+	empty := make([]byte, Structs.DOSSectorSize)
+	sectors := []int64{0}
+	for _, partition := range dos.Partitions {
+		if partition.Type == 'e' {
+			sectors = append(sectors, partition.Start)
+		}
+	}
+	for _, logical := range dos.Logicals {
+		sectors = append(sectors, logical.EBR)
+	}
+	for _, position := range sectors {
+		if err := Utilities.WriteObject(target, empty, position); err != nil {
+			errMsg := fmt.Sprintf("Error: No se pudo borrar la tabla de DOS en la posición %d: %v", position, err)
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+	}
+
+	mbr := Structs.MBR{Version: dos.Format, MbrSize: dos.Size, CreationDate: dos.CreationDate, Signature: dos.Signature, Fit: [1]byte{dos.Fit}}
+	var extended *DOSImagePartition
+	for k, partition := range dos.Partitions {
+		slot := &mbr.Partitions[partition.Slot]
+		copy(slot.Status[:], "0")
+		slot.Type[0] = partition.Type
+		slot.Fit[0] = partition.Fit
+		slot.Start = partition.Start
+		slot.Size = partition.Size
+		copy(slot.Name[:], partition.Name)
+		slot.Correlative = int32(k + 1)
+		if partition.Type == 'e' {
+			extended = &dos.Partitions[k]
+		}
+		logs += fmt.Sprintf("Partición %s (%c): inicio %d, tamaño %d bytes\n", partition.Name, partition.Type, partition.Start, partition.Size)
+	}
+	if err := WriteMBR(target, mbr); err != nil {
+		errMsg := fmt.Sprintf("Error al escribir el MBR en el archivo: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+
+	// Cada lógica lleva su EBR justo antes de sus datos; si la primera no queda al inicio de la
+	// extendida, ahí va el EBR inicial vacío que apunta a ella
+	if extended != nil {
+		ebrSize := EBRSize(dos.Format)
+		head := Structs.EBR{PartFit: extended.Fit, PartStart: extended.Start, PartNext: -1}
+		if len(dos.Logicals) > 0 {
+			head.PartNext = dos.Logicals[0].Start - ebrSize
+		}
+		if head.PartNext != extended.Start {
+			if err := WriteEBR(target, head, extended.Start); err != nil {
+				errMsg := fmt.Sprintf("Error: No se pudo escribir el EBR inicial: %v", err)
+				logs += errMsg + "\n"
+				return logs, fmt.Errorf(errMsg)
+			}
+		}
+		for k, logical := range dos.Logicals {
+			ebr := Structs.EBR{PartFit: logical.Fit, PartStart: logical.Start, PartSize: logical.Size, PartNext: -1}
+			copy(ebr.PartName[:], logical.Name)
+			if k+1 < len(dos.Logicals) {
+				ebr.PartNext = dos.Logicals[k+1].Start - ebrSize
+			}
+			if err := WriteEBR(target, ebr, logical.Start-ebrSize); err != nil {
+				errMsg := fmt.Sprintf("Error: No se pudo escribir el EBR de la partición %s: %v", logical.Name, err)
+				logs += errMsg + "\n"
+				return logs, fmt.Errorf(errMsg)
+			}
+			logs += fmt.Sprintf("Partición %s (l): inicio %d, tamaño %d bytes\n", logical.Name, logical.Start, logical.Size)
+		}
+	}
+
+	logs += fmt.Sprintf("Disco: %d bytes, %d bits\n", dos.Size, formatBits(dos.Format))
+	logs += "======FIN IMPORTMBR======\n"
+	return logs + fmt.Sprintf("IMPORTMBR: Imagen %s importada al disco %s", image, path), nil
+}
@@ -0,0 +1,158 @@
+package DiskManagement
+
+import (
+	"backend/Structs"
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDOSCHS(t *testing.T) {
+	// Un cilindro tiene 255 cabezas de 63 sectores
+	const cylinder = 255 * 63
+	tests := []struct {
+		name string
+		lba  uint32
+		want [3]byte
+	}{
+		{"sector 0", 0, [3]byte{0, 1, 0}},
+		{"último sector de la pista", 62, [3]byte{0, 63, 0}},
+		{"siguiente cabeza", 63, [3]byte{1, 1, 0}},
+		{"sector 2048", 2048, [3]byte{32, 33, 0}},
+		{"cilindro 1", cylinder, [3]byte{0, 1, 1}},
+		{"cilindro 256 usa los bits altos del sector", 256 * cylinder, [3]byte{0, 0x41, 0}},
+		{"último sector del cilindro 1023", 1024*cylinder - 1, [3]byte{254, 0xFF, 0xFF}},
+		{"más allá del cilindro 1023", 1024 * cylinder, [3]byte{0xFE, 0xFF, 0xFF}},
+	}
+	for _, test := range tests {
+		if got := Structs.DOSCHS(test.lba); got != test.want {
+			t.Errorf("%s: DOSCHS(%d) = %v, se esperaba %v", test.name, test.lba, got, test.want)
+		}
+	}
+}
+
+// readDOSSector lee la tabla del sector indicado de una imagen
+func readDOSSector(t *testing.T, file *os.File, sector uint32) Structs.DOSMBR {
+	t.Helper()
+	var table Structs.DOSMBR
+	data := make([]byte, Structs.DOSSectorSize)
+	if _, err := file.ReadAt(data, int64(sector)*Structs.DOSSectorSize); err != nil {
+		t.Fatal(err)
+	}
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &table); err != nil {
+		t.Fatal(err)
+	}
+	if table.BootSignature != Structs.DOSBootSignature {
+		t.Fatalf("el sector %d no tiene la firma 0x55AA", sector)
+	}
+	return table
+}
+
+// TestExportImportMBR exporta un disco con primarias y lógicas, revisa la tabla DOS de la imagen tal
+// como la leería fdisk y luego la importa en un disco nuevo que debe quedar igual al original
+func TestExportImportMBR(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "disco.mia")
+	image := filepath.Join(dir, "disco.img")
+	imported := filepath.Join(dir, "importado.mia")
+
+	if _, err := Mkdisk(1, "ff", "m", path, "none", "32", "mbr", 0); err != nil {
+		t.Fatal(err)
+	}
+	partitions := []struct {
+		name  string
+		type_ string
+		size  int
+	}{
+		{"p1", "p", 100},
+		{"ext", "e", 400},
+		{"l1", "l", 100},
+		{"l2", "l", 50},
+		{"l3", "l", 120},
+		{"p2", "p", 200},
+	}
+	for _, partition := range partitions {
+		if _, err := Fdisk(partition.size, path, partition.name, "k", partition.type_, "w"); err != nil {
+			t.Fatalf("fdisk %s: %v", partition.name, err)
+		}
+	}
+	original := diskLayout(t, path, true)
+
+	if _, err := ExportMBR(path, image); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(image)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	// El MBR tiene las primarias y la extendida con sus tipos; las entradas sin usar quedan vacías
+	mbr := readDOSSector(t, file, 0)
+	wantTypes := []byte{Structs.DOSTypeLinux, Structs.DOSTypeExtended, Structs.DOSTypeLinux, Structs.DOSTypeEmpty}
+	var extended Structs.DOSPartitionEntry
+	for i, entry := range mbr.Entries {
+		if entry.Type != wantTypes[i] {
+			t.Errorf("entrada %d: tipo %#x, se esperaba %#x", i, entry.Type, wantTypes[i])
+		}
+		if !entry.Used() {
+			continue
+		}
+		if entry.CHSFirst != Structs.DOSCHS(entry.LBAStart) || entry.CHSLast != Structs.DOSCHS(entry.LBAStart+entry.Sectors-1) {
+			t.Errorf("entrada %d: el CHS no coincide con el LBA", i)
+		}
+		if entry.IsExtended() {
+			extended = entry
+		}
+	}
+
+	// Cada EBR de DOS describe su lógica relativa a sí mismo y el siguiente relativo a la extendida
+	var names []string
+	for ebr, count := extended.LBAStart, 0; ; count++ {
+		if count > 16 {
+			t.Fatal("la cadena de EBR de la imagen no termina")
+		}
+		if ebr < extended.LBAStart || ebr >= extended.LBAStart+extended.Sectors {
+			t.Fatalf("EBR en el sector %d fuera de la extendida", ebr)
+		}
+		table := readDOSSector(t, file, ebr)
+		logical, next := table.Entries[0], table.Entries[1]
+		if logical.Type != Structs.DOSTypeLinux || logical.LBAStart == 0 {
+			t.Fatalf("EBR del sector %d: lógica con tipo %#x en %d", ebr, logical.Type, logical.LBAStart)
+		}
+		name := make([]byte, 2)
+		if _, err := file.ReadAt(name, int64(ebr+logical.LBAStart)*Structs.DOSSectorSize); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, string(name))
+		if !next.Used() {
+			break
+		}
+		if !next.IsExtended() {
+			t.Errorf("EBR del sector %d: el enlace tiene tipo %#x", ebr, next.Type)
+		}
+		ebr = extended.LBAStart + next.LBAStart
+	}
+	if got := strings.Join(names, " "); got != "l1 l2 l3" {
+		t.Errorf("lógicas en la cadena de la imagen: %s, se esperaba l1 l2 l3", got)
+	}
+
+	if _, err := ImportMBR(image, imported); err != nil {
+		t.Fatal(err)
+	}
+	if got := diskLayout(t, imported, false); strings.Join(got, ", ") != strings.Join(original, ", ") {
+		t.Errorf("particiones después de importar:\n%v\nen el original:\n%v", got, original)
+	}
+	converted, err := os.Open(imported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer converted.Close()
+	if format, err := DiskFormat(converted); err != nil || format != Structs.Format32 {
+		t.Errorf("formato del disco importado = %d, %v; se esperaba el de 32 bits del original", format, err)
+	}
+}
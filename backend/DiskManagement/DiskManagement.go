@@ -3,16 +3,15 @@ package DiskManagement
 import (
 	"backend/Structs"
 	"backend/Utilities"
-	"bytes"
-	"encoding/binary"
 	"fmt"
+	"math"
 	"math/rand"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
-	"unsafe"
 )
 
 // Estructura para representar una partición montada
@@ -22,6 +21,7 @@ type MountedPartition struct {
 	ID       string
 	Status   byte // 0: no montada, 1: montada
 	LoggedIn bool // true: usuario ha iniciado sesión, false: no ha iniciado sesión
+	Unclean  bool // true: el montaje anterior no terminó con unmount; conviene revisar la consistencia
 
 }
 
@@ -30,7 +30,7 @@ func GetMountedPartitionByID(id string) (*MountedPartition, error) {
 	// Iterar sobre todas las particiones montadas
 	for _, partitions := range mountedPartitions {
 		for _, partition := range partitions {
-			if partition.ID == id {
+			if SameID(partition.ID, id) {
 				// Retorna un puntero a la partición encontrada
 				return &partition, nil
 			}
@@ -73,32 +73,84 @@ func GetMountedPartitionsString() string {
 func GetMountedPartitions() map[string][]MountedPartition {
 	return mountedPartitions
 }
-func Rmdisk(path string) (string, error) {
-	fmt.Println("======Start RMDISK======")
-	fmt.Println("Path:", path)
+
+// SnapshotMounts devuelve una copia de la tabla de montajes para poder restaurarla después
+func SnapshotMounts() map[string][]MountedPartition {
+	snapshot := make(map[string][]MountedPartition, len(mountedPartitions))
+	for diskID, partitions := range mountedPartitions {
+		snapshot[diskID] = append([]MountedPartition(nil), partitions...)
+	}
+	return snapshot
+}
+
+// RestoreMounts reemplaza la tabla de montajes por una copia tomada con SnapshotMounts
+func RestoreMounts(snapshot map[string][]MountedPartition) {
+	mountedPartitions = snapshot
+	saveMounts()
+}
+
+// Rmdisk mueve el disco a la papelera. Si tiene particiones montadas falla, salvo con force, que
+// primero las desmonta (y con eso cierra la sesión que hubiera en ellas).
+func Rmdisk(path string, force bool) (string, error) {
+	var logs string
+	logs += "======Start RMDISK======\n"
+	logs += fmt.Sprintf("Path: %s\n", path)
 
 	// Verificar si el archivo existe
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		fmt.Println("Error: El DISCO no existe en la ruta especificada.")
-		return "Error: El DISCO no existe en la ruta especificada.", nil
+		errMsg := fmt.Sprintf("Error: El disco no existe en la ruta especificada: %s", path)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+
+	mounted := append([]MountedPartition(nil), mountedPartitions[generateDiskID(path)]...)
+	var ids []string
+	session := ""
+	for _, partition := range mounted {
+		ids = append(ids, partition.ID)
+		if partition.LoggedIn {
+			session = fmt.Sprintf(", con la sesión activa en %s", partition.ID)
+		}
+	}
+	if len(mounted) > 0 && !force {
+		errMsg := fmt.Sprintf("Error: El disco %s tiene particiones montadas (%s%s); desmóntelas o use -force.", path, strings.Join(ids, ", "), session)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	for _, partition := range mounted {
+		unmountLogs, err := Unmount(partition.ID)
+		logs += unmountLogs + "\n"
+		if err != nil {
+			return logs, err
+		}
+	}
+
+	// Mover el disco a la papelera (en modo atómico se devuelve a su lugar al deshacer)
+	entry, err := moveToTrash(path, ids)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error: No se pudo mover el disco a la papelera: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	logs += fmt.Sprintf("Papelera: %s (entrada %s)\n", entry.File(), entry.ID)
+	if retention, err := TrashRetention(); err == nil && retention > 0 {
+		logs += fmt.Sprintf("Se eliminará definitivamente después de %s\n", formatRetention(retention))
 	}
 
-	// Eliminar el archivo de disco
-	err := os.Remove(path)
+	purged, err := PurgeTrash()
+	logs += purged
 	if err != nil {
-		fmt.Println("Error: No se pudo eliminar el archivo:", err)
-		return "Error: No se pudo eliminar el archivo", err
+		logs += fmt.Sprintf("No se pudo purgar la papelera: %v\n", err)
 	}
-	fmt.Println("Disco eliminado exitosamente.")
 
-	fmt.Println("======End RMDISK======")
-	return "Disco eliminado exitosamente en: " + path, nil
+	logs += "======FIN RMDISK======\n"
+	return logs + fmt.Sprintf("RMDISK: Disco %s movido a la papelera; restoredisk -path=%s lo recupera", path, path), nil
 }
 
 func MarkPartitionAsLoggedOut(id string) error {
 	for diskPath, partitions := range mountedPartitions {
 		for i, partition := range partitions {
-			if partition.ID == id {
+			if SameID(partition.ID, id) {
 				mountedPartitions[diskPath][i].LoggedIn = false
 				return nil
 			}
@@ -111,7 +163,7 @@ func MarkPartitionAsLoggedOut(id string) error {
 func MarkPartitionAsLoggedIn(id string) {
 	for diskID, partitions := range mountedPartitions {
 		for i, partition := range partitions {
-			if partition.ID == id {
+			if SameID(partition.ID, id) {
 				mountedPartitions[diskID][i].LoggedIn = true
 				fmt.Printf("Partición con ID %s marcada como logueada.\n", id)
 				return
@@ -121,7 +173,7 @@ func MarkPartitionAsLoggedIn(id string) {
 	fmt.Printf("No se encontró la partición con ID %s para marcarla como logueada.\n", id)
 }
 
-func Mkdisk(size int, fit string, unit string, path string) (string, error) {
+func Mkdisk(size int, fit string, unit string, path string, zero string, format string, table string, entries int) (string, error) {
 	// Variable para acumular los mensajes
 	var logs string
 
@@ -131,6 +183,9 @@ func Mkdisk(size int, fit string, unit string, path string) (string, error) {
 	logs += fmt.Sprintf("Fit: %s\n", fit)
 	logs += fmt.Sprintf("Unit: %s\n", unit)
 	logs += fmt.Sprintf("Path: %s\n", path)
+	logs += fmt.Sprintf("Zero: %s\n", zero)
+	logs += fmt.Sprintf("Format: %s\n", format)
+	logs += fmt.Sprintf("Table: %s\n", table)
 
 	// Validar fit bf/ff/wf
 	if fit != "bf" && fit != "wf" && fit != "ff" {
@@ -153,14 +208,41 @@ func Mkdisk(size int, fit string, unit string, path string) (string, error) {
 		return logs, fmt.Errorf(errMsg)
 	}
 
-	// Crear archivo
-	err := Utilities.CreateFile(path)
-	if err != nil {
-		errMsg := fmt.Sprintf("Error al crear el archivo: %v", err)
+	// Validar el llenado full - none
+	if zero != "full" && zero != "none" {
+		errMsg := "Error: Zero debe ser full o none"
 		logs += errMsg + "\n"
 		return logs, fmt.Errorf(errMsg)
 	}
 
+	// Validar el formato 32 - 64
+	if format != "32" && format != "64" {
+		errMsg := "Error: Format debe ser 32 o 64"
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+
+	// Validar la tabla mbr - gpt; la GPT siempre usa el formato de 64 bits
+	if table != "mbr" && table != "gpt" {
+		errMsg := "Error: Table debe ser mbr o gpt"
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	if table == "gpt" {
+		if entries <= 0 {
+			errMsg := "Error: Entries debe ser mayor a 0"
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+		// Cada entrada tiene que poder montarse: su ID lleva el número de la entrada
+		if err := CheckEntryIDs(entries); err != nil {
+			errMsg := fmt.Sprintf("Error: %v", err)
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+		format = "64"
+	}
+
 	// Asignar tamaño
 	if unit == "k" {
 		size = size * 1024
@@ -168,6 +250,28 @@ func Mkdisk(size int, fit string, unit string, path string) (string, error) {
 		size = size * 1024 * 1024
 	}
 
+	// En el formato de 32 bits el tamaño del disco tiene que caber en un int32
+	if format == "32" && size > math.MaxInt32 {
+		errMsg := fmt.Sprintf("Error: Un disco de %d bytes no cabe en el formato de 32 bits (máximo %d); use -format=64", size, math.MaxInt32)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+
+	// La GPT necesita espacio para sus dos encabezados y sus dos arreglos de entradas
+	if table == "gpt" && int64(size) <= GPTReservedSize(entries) {
+		errMsg := fmt.Sprintf("Error: Un disco de %d bytes no tiene espacio para una GPT de %d entradas, que ocupa %d bytes con su respaldo", size, entries, GPTReservedSize(entries))
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+
+	// Crear archivo
+	err := Utilities.CreateFile(path)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error al crear el archivo: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+
 	// Abrir archivo binario
 	file, err := Utilities.OpenFile(path)
 	if err != nil {
@@ -177,18 +281,29 @@ func Mkdisk(size int, fit string, unit string, path string) (string, error) {
 	}
 	defer file.Close()
 
-	// Escribir los 0 en el archivo
-	for i := 0; i < size; i++ {
-		if err := Utilities.WriteObject(file, byte(0), int64(i)); err != nil {
-			errMsg := fmt.Sprintf("Error al escribir en el archivo: %v", err)
-			logs += errMsg + "\n"
-			return logs, fmt.Errorf(errMsg)
-		}
+	// Llenar el disco: con "full" se escriben los ceros por bloques, con "none" solo se fija el
+	// tamaño y el archivo queda disperso (el sistema lo lee como ceros sin ocupar espacio). Si el
+	// archivo ya existía primero se vacía, para que no queden sus bytes ni su tamaño anterior.
+	err = Utilities.TruncateFile(file, 0)
+	if err == nil && zero == "none" {
+		err = Utilities.TruncateFile(file, int64(size))
+		Utilities.ReportProgress("mkdisk", int64(size), int64(size))
+	} else if err == nil {
+		err = zeroRange(file, 0, int64(size), "mkdisk")
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("Error al escribir en el archivo: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
 	}
 
 	// Crear MBR
 	var newMRB Structs.MBR
-	newMRB.MbrSize = int32(size)
+	newMRB.Version = Structs.Format64
+	if format == "32" {
+		newMRB.Version = Structs.Format32
+	}
+	newMRB.MbrSize = int64(size)
 	newMRB.Signature = rand.Int31() // Número random rand.Int31() genera solo números no negativos
 	copy(newMRB.Fit[:], fit)
 
@@ -197,26 +312,41 @@ func Mkdisk(size int, fit string, unit string, path string) (string, error) {
 	formattedDate := currentTime.Format("2006-01-02")
 	copy(newMRB.CreationDate[:], formattedDate)
 
-	// Escribir el MBR en el archivo
-	if err := Utilities.WriteObject(file, newMRB, 0); err != nil {
+	// Escribir el MBR en el archivo, o la GPT con los datos del MBR
+	if table == "gpt" {
+		gpt, err := NewGPTTable(newMRB, entries)
+		if err == nil {
+			err = WriteTable(file, gpt)
+		}
+		if err != nil {
+			errMsg := fmt.Sprintf("Error al escribir la GPT en el archivo: %v", err)
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+	} else if err := WriteMBR(file, newMRB); err != nil {
 		errMsg := fmt.Sprintf("Error al escribir el MBR en el archivo: %v", err)
 		logs += errMsg + "\n"
 		return logs, fmt.Errorf(errMsg)
 	}
 
-	var TempMBR Structs.MBR
-	// Leer el MBR del archivo
-	if err := Utilities.ReadObject(file, &TempMBR, 0); err != nil {
+	// Leer la tabla del archivo
+	TempTable, err := ReadTable(file)
+	if err != nil {
 		errMsg := fmt.Sprintf("Error al leer el MBR del archivo: %v", err)
 		logs += errMsg + "\n"
 		return logs, fmt.Errorf(errMsg)
 	}
+	TempMBR := TempTable.MBR
 
 	// Formatear los datos del MBR manualmente y agregar al log
 	logs += fmt.Sprintf("MBR Size: %d\n", TempMBR.MbrSize)
 	logs += fmt.Sprintf("MBR Signature: %d\n", TempMBR.Signature)
 	logs += fmt.Sprintf("MBR Fit: %s\n", string(TempMBR.Fit[:]))
 	logs += fmt.Sprintf("MBR Creation Date: %s\n", string(TempMBR.CreationDate[:]))
+	logs += fmt.Sprintf("MBR Format: %d bits\n", formatBits(TempMBR.Version))
+	if TempTable.GPT != nil {
+		logs += fmt.Sprintf("GPT: %d entradas, espacio para particiones de %d a %d\n", TempTable.GPT.EntryCount, TempTable.GPT.FirstUsable, TempTable.GPT.LastUsable)
+	}
 
 	logs += "======FIN MKDISK======\n"
 	return logs + fmt.Sprintf("MKDISK: Disco creado exitosamente en: %s", path), nil
@@ -272,40 +402,54 @@ func Fdisk(size int, path string, name string, unit string, type_ string, fit st
 	}
 	defer file.Close()
 
-	var TempMBR Structs.MBR
 	// Leer el objeto desde el archivo binario
-	if err := Utilities.ReadObject(file, &TempMBR, 0); err != nil {
+	table, err := ReadTable(file)
+	if err != nil {
 		errMsg := "Error: Could not read MBR from file"
 		logs += errMsg + "\n"
 		return logs, fmt.Errorf(errMsg)
 	}
 
 	// Formatear el MBR y agregarlo al log
-	logs += fmt.Sprintf("MBR Size: %d\n", TempMBR.MbrSize)
-	logs += fmt.Sprintf("MBR Signature: %d\n", TempMBR.Signature)
-	logs += fmt.Sprintf("MBR Fit: %s\n", string(TempMBR.Fit[:]))
-	logs += fmt.Sprintf("MBR Creation Date: %s\n", string(TempMBR.CreationDate[:]))
+	logs += fmt.Sprintf("MBR Size: %d\n", table.MBR.MbrSize)
+	logs += fmt.Sprintf("MBR Signature: %d\n", table.MBR.Signature)
+	logs += fmt.Sprintf("MBR Fit: %s\n", string(table.MBR.Fit[:]))
+	logs += fmt.Sprintf("MBR Creation Date: %s\n", string(table.MBR.CreationDate[:]))
 	logs += "-------------\n"
 
 	// Validaciones de las particiones
 	var primaryCount, extendedCount, totalPartitions int
-	var usedSpace int32 = 0
+	var lastCorrelative int32
 
-	for i := 0; i < 4; i++ {
-		if TempMBR.Partitions[i].Size != 0 {
+	for i := range table.Partitions {
+		if table.Partitions[i].Size != 0 {
 			totalPartitions++
-			usedSpace += TempMBR.Partitions[i].Size
+			lastCorrelative = max(lastCorrelative, table.Partitions[i].Correlative)
 
-			if TempMBR.Partitions[i].Type[0] == 'p' {
+			if table.Partitions[i].Type[0] == 'p' {
 				primaryCount++
-			} else if TempMBR.Partitions[i].Type[0] == 'e' {
+			} else if table.Partitions[i].Type[0] == 'e' {
 				extendedCount++
 			}
 		}
 	}
 
-	// Validar que no se exceda el número máximo de particiones primarias y extendidas
-	if totalPartitions >= 4 {
+	// En una GPT todas las particiones son primarias y cada una ocupa una entrada
+	if table.GPT != nil {
+		if type_ != "p" {
+			errMsg := "Error: Un disco con tabla GPT solo admite particiones primarias."
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+		if totalPartitions >= len(table.Partitions) {
+			errMsg := fmt.Sprintf("Error: Las %d entradas de la GPT ya están ocupadas.", len(table.Partitions))
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+	}
+
+	// Validar que no se exceda el número máximo de particiones primarias y extendidas (las lógicas no usan slots del MBR)
+	if type_ != "l" && totalPartitions >= 4 && table.GPT == nil {
 		errMsg := "Error: No se pueden crear más de 4 particiones primarias o extendidas en total."
 		logs += errMsg + "\n"
 		return logs, fmt.Errorf(errMsg)
@@ -325,31 +469,32 @@ func Fdisk(size int, path string, name string, unit string, type_ string, fit st
 		return logs, fmt.Errorf(errMsg)
 	}
 
-	// Validar que el tamaño de la nueva partición no exceda el tamaño del disco
-	if usedSpace+int32(size) > TempMBR.MbrSize {
-		errMsg := "Error: No hay suficiente espacio en el disco para crear esta partición."
-		logs += errMsg + "\n"
-		return logs, fmt.Errorf(errMsg)
-	}
-
-	// Determinar la posición de inicio de la nueva partición
-	var gap int32 = int32(binary.Size(TempMBR))
-	if totalPartitions > 0 {
-		gap = TempMBR.Partitions[totalPartitions-1].Start + TempMBR.Partitions[totalPartitions-1].Size
+	// Las particiones primarias y extendidas se colocan en un espacio libre según el ajuste del disco
+	var gap int64
+	if type_ == "p" || type_ == "e" {
+		chosen, err := PlacePartition(table.Gaps(), int64(size), table.MBR.Fit[0], name)
+		if err != nil {
+			errMsg := fmt.Sprintf("Error: %v", err)
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+		gap = chosen.Start
+		logs += fmt.Sprintf("Espacio libre elegido (%s): inicio %d, tamaño %d\n", FitName(table.MBR.Fit[0]), chosen.Start, chosen.Size)
 	}
 
 	// Encontrar una posición vacía para la nueva partición
-	for i := 0; i < 4; i++ {
-		if TempMBR.Partitions[i].Size == 0 {
+	for i := range table.Partitions {
+		if table.Partitions[i].Size == 0 {
 			if type_ == "p" || type_ == "e" {
 				// Crear partición primaria o extendida
-				TempMBR.Partitions[i].Size = int32(size)
-				TempMBR.Partitions[i].Start = gap
-				copy(TempMBR.Partitions[i].Name[:], name)
-				copy(TempMBR.Partitions[i].Fit[:], fit)
-				copy(TempMBR.Partitions[i].Status[:], "0")
-				copy(TempMBR.Partitions[i].Type[:], type_)
-				TempMBR.Partitions[i].Correlative = int32(totalPartitions + 1)
+				table.Partitions[i].Size = int64(size)
+				table.Partitions[i].Start = gap
+				copy(table.Partitions[i].Name[:], name)
+				copy(table.Partitions[i].Fit[:], fit)
+				copy(table.Partitions[i].Status[:], "0")
+				copy(table.Partitions[i].Type[:], type_)
+				// Después de fdisk -delete contar las particiones repetiría un correlativo; se sigue al mayor
+				table.Partitions[i].Correlative = lastCorrelative + 1
 
 				if type_ == "e" {
 					// Inicializar el primer EBR en la partición extendida
@@ -361,7 +506,11 @@ func Fdisk(size int, path string, name string, unit string, type_ string, fit st
 						PartNext:  -1,
 					}
 					copy(ebr.PartName[:], "")
-					Utilities.WriteObject(file, ebr, int64(ebrStart))
+					if err := WriteEBR(file, ebr, ebrStart); err != nil {
+						errMsg := fmt.Sprintf("Error: No se pudo escribir el EBR inicial: %v", err)
+						logs += errMsg + "\n"
+						return logs, fmt.Errorf(errMsg)
+					}
 				}
 
 				break
@@ -371,59 +520,13 @@ func Fdisk(size int, path string, name string, unit string, type_ string, fit st
 
 	// Manejar la creación de particiones lógicas dentro de una partición extendida
 	if type_ == "l" {
-		for i := 0; i < 4; i++ {
-			if TempMBR.Partitions[i].Type[0] == 'e' {
-				ebrPos := TempMBR.Partitions[i].Start
-				var ebr Structs.EBR
-				for {
-					Utilities.ReadObject(file, &ebr, int64(ebrPos))
-					if ebr.PartNext == -1 {
-						break
-					}
-					ebrPos = ebr.PartNext
-				}
-
-				// Calcular la posición de inicio de la nueva partición lógica
-				newEBRPos := ebr.PartStart + ebr.PartSize                    // El nuevo EBR se coloca después de la partición lógica anterior
-				logicalPartitionStart := newEBRPos + int32(binary.Size(ebr)) // El inicio de la partición lógica es justo después del EBR
-
-				// Ajustar el siguiente EBR
-				ebr.PartNext = newEBRPos
-				Utilities.WriteObject(file, ebr, int64(ebrPos))
-
-				// Crear y escribir el nuevo EBR
-				newEBR := Structs.EBR{
-					PartFit:   fit[0],
-					PartStart: logicalPartitionStart,
-					PartSize:  int32(size),
-					PartNext:  -1,
-				}
-				copy(newEBR.PartName[:], name)
-				Utilities.WriteObject(file, newEBR, int64(newEBRPos))
-
-				// Agregar el nuevo EBR creado al log
-				logs += "Nuevo EBR creado:\n"
-				logs += fmt.Sprintf("EBR Start: %d\n", newEBR.PartStart)
-				logs += fmt.Sprintf("EBR Size: %d\n", newEBR.PartSize)
-				logs += fmt.Sprintf("EBR Next: %d\n", newEBR.PartNext)
-				logs += "\n"
-
-				// Imprimir todos los EBRs en la partición extendida
-				logs += "Imprimiendo todos los EBRs en la partición extendida:\n"
-				ebrPos = TempMBR.Partitions[i].Start
-				for {
-					err := Utilities.ReadObject(file, &ebr, int64(ebrPos))
-					if err != nil {
-						logs += fmt.Sprintf("Error al leer EBR: %v\n", err)
-						break
-					}
-					logs += fmt.Sprintf("EBR Start: %d, Size: %d, Next: %d\n", ebr.PartStart, ebr.PartSize, ebr.PartNext)
-					if ebr.PartNext == -1 {
-						break
-					}
-					ebrPos = ebr.PartNext
+		for i := range table.Partitions {
+			if table.Partitions[i].Type[0] == 'e' {
+				logicalLogs, err := createLogicalPartition(file, table.Partitions[i], int64(size), name, fit)
+				logs += logicalLogs
+				if err != nil {
+					return logs, err
 				}
-
 				break
 			}
 		}
@@ -431,104 +534,520 @@ func Fdisk(size int, path string, name string, unit string, type_ string, fit st
 	}
 
 	// Sobrescribir el MBR
-	if err := Utilities.WriteObject(file, TempMBR, 0); err != nil {
-		errMsg := "Error: Could not write MBR to file"
+	if err := WriteTable(file, table); err != nil {
+		errMsg := fmt.Sprintf("Error: Could not write MBR to file: %v", err)
 		logs += errMsg + "\n"
 		return logs, fmt.Errorf(errMsg)
 	}
 
-	var TempMBR2 Structs.MBR
 	// Leer el objeto nuevamente para verificar
-	if err := Utilities.ReadObject(file, &TempMBR2, 0); err != nil {
+	table2, err := ReadTable(file)
+	if err != nil {
 		errMsg := "Error: Could not read MBR from file after writing"
 		logs += errMsg + "\n"
 		return logs, fmt.Errorf(errMsg)
 	}
 
 	// Agregar el MBR actualizado al log
-	logs += fmt.Sprintf("MBR Size: %d\n", TempMBR2.MbrSize)
-	logs += fmt.Sprintf("MBR Signature: %d\n", TempMBR2.Signature)
-	logs += fmt.Sprintf("MBR Fit: %s\n", string(TempMBR2.Fit[:]))
-	logs += fmt.Sprintf("MBR Creation Date: %s\n", string(TempMBR2.CreationDate[:]))
+	logs += fmt.Sprintf("MBR Size: %d\n", table2.MBR.MbrSize)
+	logs += fmt.Sprintf("MBR Signature: %d\n", table2.MBR.Signature)
+	logs += fmt.Sprintf("MBR Fit: %s\n", string(table2.MBR.Fit[:]))
+	logs += fmt.Sprintf("MBR Creation Date: %s\n", string(table2.MBR.CreationDate[:]))
 
 	logs += "======FIN FDISK======\n"
 	return logs + fmt.Sprintf("FDISK: Partición %s creada exitosamente en: %s", name, path), nil
 }
 
-func Mount(path string, name string) (string, error) {
+// ebrEntry es un EBR de la cadena junto con la posición donde está escrito
+type ebrEntry struct {
+	Position int64
+	EBR      Structs.EBR
+}
+
+// readEBRChain recorre la cadena de EBR de una partición extendida desde su inicio
+func readEBRChain(file *os.File, extended Structs.Partition) ([]ebrEntry, error) {
+	var chain []ebrEntry
+	position := extended.Start
+	for position != -1 {
+		// Los EBR van en orden dentro de la extendida; otra cosa indica una cadena dañada
+		if position < extended.Start || position >= extended.Start+extended.Size {
+			return chain, fmt.Errorf("la cadena de EBR apunta fuera de la partición extendida (posición %d)", position)
+		}
+		if len(chain) > 0 && position <= chain[len(chain)-1].Position {
+			return chain, fmt.Errorf("la cadena de EBR no avanza en la posición %d", position)
+		}
+		ebr, err := ReadEBR(file, position)
+		if err != nil {
+			return chain, fmt.Errorf("no se pudo leer el EBR en la posición %d: %v", position, err)
+		}
+		chain = append(chain, ebrEntry{Position: position, EBR: ebr})
+		position = ebr.PartNext
+	}
+	return chain, nil
+}
+
+// logicalGaps devuelve los espacios libres de la partición extendida. Cada partición lógica ocupa
+// su EBR y sus datos; el EBR inicial sin partición no ocupa espacio porque la primera lógica lo reemplaza.
+func logicalGaps(extended Structs.Partition, chain []ebrEntry) []Gap {
+	var used []Gap
+	for _, entry := range chain {
+		if entry.EBR.PartSize > 0 {
+			used = append(used, Gap{Start: entry.Position, Size: entry.EBR.PartStart + entry.EBR.PartSize - entry.Position})
+		}
+	}
+	return FreeGaps(extended.Start, extended.Start+extended.Size, used)
+}
+
+// createLogicalPartition coloca una partición lógica en un espacio libre de la extendida según su ajuste
+// y enlaza su EBR en la cadena, en orden de posición
+func createLogicalPartition(file *os.File, extended Structs.Partition, size int64, name string, fit string) (string, error) {
+	var logs string
+
+	chain, err := readEBRChain(file, extended)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+
+	// Cada lógica necesita espacio para su EBR y sus datos
+	format, err := DiskFormat(file)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	ebrSize := EBRSize(format)
+	chosen, err := PlaceLogical(logicalGaps(extended, chain), ebrSize, size, extended.Fit[0], name)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	logs += fmt.Sprintf("Espacio libre elegido en la extendida (%s): inicio %d, tamaño %d\n", FitName(extended.Fit[0]), chosen.Start, chosen.Size)
+
+	newEBR := Structs.EBR{
+		PartFit:   fit[0],
+		PartStart: chosen.Start + ebrSize, // El inicio de la partición lógica es justo después del EBR
+		PartSize:  size,
+		PartNext:  -1,
+	}
+	copy(newEBR.PartName[:], name)
+
+	if chosen.Start == extended.Start {
+		// El EBR inicial estaba vacío: la nueva lógica ocupa su lugar y conserva su siguiente
+		newEBR.PartNext = chain[0].EBR.PartNext
+	} else {
+		// Enlazar después del último EBR que está antes del espacio elegido
+		previous := 0
+		for i, entry := range chain {
+			if entry.Position < chosen.Start {
+				previous = i
+			}
+		}
+		newEBR.PartNext = chain[previous].EBR.PartNext
+		chain[previous].EBR.PartNext = chosen.Start
+		if err := WriteEBR(file, chain[previous].EBR, chain[previous].Position); err != nil {
+			errMsg := fmt.Sprintf("Error: No se pudo actualizar el EBR en la posición %d", chain[previous].Position)
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+	}
+	if err := WriteEBR(file, newEBR, chosen.Start); err != nil {
+		errMsg := fmt.Sprintf("Error: No se pudo escribir el nuevo EBR: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+
+	// Agregar el nuevo EBR creado al log
+	logs += "Nuevo EBR creado:\n"
+	logs += fmt.Sprintf("EBR Start: %d\n", newEBR.PartStart)
+	logs += fmt.Sprintf("EBR Size: %d\n", newEBR.PartSize)
+	logs += fmt.Sprintf("EBR Next: %d\n", newEBR.PartNext)
+	logs += "\n"
+
+	// Imprimir todos los EBRs en la partición extendida
+	logs += "Imprimiendo todos los EBRs en la partición extendida:\n"
+	chain, err = readEBRChain(file, extended)
+	for _, entry := range chain {
+		logs += fmt.Sprintf("EBR Start: %d, Size: %d, Next: %d\n", entry.EBR.PartStart, entry.EBR.PartSize, entry.EBR.PartNext)
+	}
+	if err != nil {
+		logs += fmt.Sprintf("Error al leer EBR: %v\n", err)
+	}
+	return logs, nil
+}
+
+// IsMounted indica si la partición name del disco path está en la tabla de montajes
+func IsMounted(path string, name string) bool {
+	for _, partition := range mountedPartitions[generateDiskID(path)] {
+		if partition.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Tamaño de los bloques de ceros que se escriben de una vez
+const zeroChunkSize = 1024 * 1024
+
+// zeroRange llena con ceros size bytes del disco a partir de start, un bloque por escritura.
+// Si task no está vacío se reporta el avance con ese nombre.
+func zeroRange(file *os.File, start int64, size int64, task string) error {
+	zeros := make([]byte, min(zeroChunkSize, size))
+	for offset := int64(0); offset < size; offset += zeroChunkSize {
+		n := min(zeroChunkSize, size-offset)
+		if err := Utilities.WriteObject(file, zeros[:n], start+offset); err != nil {
+			return err
+		}
+		if task != "" {
+			Utilities.ReportProgress(task, offset+n, size)
+		}
+	}
+	return nil
+}
+
+// DeletePartition elimina la partición name del disco. Con mode "fast" solo se libera el slot del MBR
+// o se desenlaza el EBR; con "full" además se llenan con ceros los bytes de la partición.
+// Al eliminar la extendida se eliminan también sus lógicas. Una partición montada no se puede eliminar.
+func DeletePartition(path string, name string, mode string) (string, error) {
+	var logs string
+	logs += "======Start FDISK DELETE======\n"
+	logs += fmt.Sprintf("Path: %s\n", path)
+	logs += fmt.Sprintf("Name: %s\n", name)
+	logs += fmt.Sprintf("Delete: %s\n", mode)
+
 	file, err := Utilities.OpenFile(path)
 	if err != nil {
-		mountedPartitionsStr := GetMountedPartitionsString()
-		return "Error: No se pudo abrir el archivo en la ruta:\n" + mountedPartitionsStr, nil
+		errMsg := fmt.Sprintf("Error: Could not open file at path: %s", path)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
 	}
 	defer file.Close()
 
-	var TempMBR Structs.MBR
-	if err := Utilities.ReadObject(file, &TempMBR, 0); err != nil {
-		mountedPartitionsStr := GetMountedPartitionsString()
-		return "Error: No se pudo leer el MBR desde el archivo:\n" + mountedPartitionsStr, nil
+	table, err := ReadTable(file)
+	if err != nil {
+		errMsg := "Error: Could not read MBR from file"
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
 	}
 
-	fmt.Printf("Buscando partición con nombre: '%s'\n", name)
+	// Buscar primero entre las particiones primarias y la extendida
+	for i := range table.Partitions {
+		partition := table.Partitions[i]
+		if partition.Size == 0 || strings.TrimRight(string(partition.Name[:]), "\x00") != name {
+			continue
+		}
 
-	partitionFound := false
-	var partition Structs.Partition
-	var partitionIndex int
+		if IsMounted(path, name) {
+			errMsg := fmt.Sprintf("Error: La partición %s está montada; desmóntela antes de eliminarla.", name)
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
 
-	// Convertir el nombre a comparar a un arreglo de bytes de longitud fija
-	nameBytes := [16]byte{}
-	copy(nameBytes[:], []byte(name))
+		// La extendida se lleva sus lógicas, así que ninguna puede estar montada
+		if partition.Type[0] == 'e' {
+			chain, _ := readEBRChain(file, partition)
+			for _, entry := range chain {
+				logicalName := strings.TrimRight(string(entry.EBR.PartName[:]), "\x00")
+				if entry.EBR.PartSize > 0 && IsMounted(path, logicalName) {
+					errMsg := fmt.Sprintf("Error: La partición lógica %s de la extendida %s está montada; desmóntela antes de eliminarla.", logicalName, name)
+					logs += errMsg + "\n"
+					return logs, fmt.Errorf(errMsg)
+				}
+				if entry.EBR.PartSize > 0 {
+					logs += fmt.Sprintf("Partición lógica %s eliminada junto con la extendida\n", logicalName)
+				}
+			}
+		}
 
-	for i := 0; i < 4; i++ {
-		if TempMBR.Partitions[i].Type[0] == 'p' && bytes.Equal(TempMBR.Partitions[i].Name[:], nameBytes[:]) {
-			partition = TempMBR.Partitions[i]
-			partitionIndex = i
-			partitionFound = true
-			break
+		if mode == "full" {
+			if err := zeroRange(file, int64(partition.Start), int64(partition.Size), "fdisk"); err != nil {
+				errMsg := fmt.Sprintf("Error: No se pudo llenar con ceros la partición %s: %v", name, err)
+				logs += errMsg + "\n"
+				return logs, fmt.Errorf(errMsg)
+			}
 		}
+
+		table.Partitions[i] = Structs.Partition{}
+		if err := WriteTable(file, table); err != nil {
+			errMsg := "Error: Could not write MBR to file"
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+
+		logs += "======FIN FDISK DELETE======\n"
+		return logs + fmt.Sprintf("FDISK: Partición %s eliminada (%s) de: %s", name, mode, path), nil
 	}
 
-	if !partitionFound {
-		mountedPartitionsStr := GetMountedPartitionsString()
-		return "Error: Partición no encontrada o no es una partición primaria:\n" + mountedPartitionsStr, nil
+	// Buscar entre las lógicas de la extendida
+	for i := range table.Partitions {
+		extended := table.Partitions[i]
+		if extended.Size == 0 || extended.Type[0] != 'e' {
+			continue
+		}
+
+		chain, err := readEBRChain(file, extended)
+		if err != nil {
+			errMsg := fmt.Sprintf("Error: %v", err)
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+		for j, entry := range chain {
+			if entry.EBR.PartSize == 0 || strings.TrimRight(string(entry.EBR.PartName[:]), "\x00") != name {
+				continue
+			}
+
+			if IsMounted(path, name) {
+				errMsg := fmt.Sprintf("Error: La partición %s está montada; desmóntela antes de eliminarla.", name)
+				logs += errMsg + "\n"
+				return logs, fmt.Errorf(errMsg)
+			}
+
+			if mode == "full" {
+				if err := zeroRange(file, int64(entry.Position), int64(entry.EBR.PartStart+entry.EBR.PartSize-entry.Position), "fdisk"); err != nil {
+					errMsg := fmt.Sprintf("Error: No se pudo llenar con ceros la partición %s: %v", name, err)
+					logs += errMsg + "\n"
+					return logs, fmt.Errorf(errMsg)
+				}
+			}
+
+			// El primer EBR marca el inicio de la cadena: se deja vacío en lugar de desenlazarlo
+			var target ebrEntry
+			if j == 0 {
+				target = ebrEntry{Position: entry.Position, EBR: Structs.EBR{PartFit: entry.EBR.PartFit, PartStart: entry.Position, PartNext: entry.EBR.PartNext}}
+			} else {
+				target = chain[j-1]
+				target.EBR.PartNext = entry.EBR.PartNext
+			}
+			if err := WriteEBR(file, target.EBR, target.Position); err != nil {
+				errMsg := fmt.Sprintf("Error: No se pudo actualizar el EBR en la posición %d", target.Position)
+				logs += errMsg + "\n"
+				return logs, fmt.Errorf(errMsg)
+			}
+
+			logs += "======FIN FDISK DELETE======\n"
+			return logs + fmt.Sprintf("FDISK: Partición lógica %s eliminada (%s) de: %s", name, mode, path), nil
+		}
 	}
 
-	// Verificar si la partición ya está montada
-	if partition.Status[0] == '1' {
-		mountedPartitionsStr := GetMountedPartitionsString()
-		return "Error: La partición ya está montada:\n" + mountedPartitionsStr, nil
+	errMsg := fmt.Sprintf("Error: No existe la partición %s en %s", name, path)
+	logs += errMsg + "\n"
+	return logs, fmt.Errorf(errMsg)
+}
+
+// FilesystemEnd devuelve la posición donde terminan las estructuras del sistema de archivos
+// que empieza en start (superbloque, bitmaps, inodos y bloques); 0 si ahí no hay un EXT2
+func FilesystemEnd(file *os.File, start int64) int64 {
+	superblock, err := ReadSuperblock(file, start)
+	if err != nil || superblock.S_magic != 0xEF53 {
+		return 0
 	}
+	return superblock.S_block_start + int64(superblock.S_blocks_count)*int64(superblock.S_block_size)
+}
 
-	// Generar el ID de la partición
-	diskID := generateDiskID(path)
+// describeLayout lista las particiones del disco en orden de posición, con sus lógicas y los espacios libres
+func describeLayout(file *os.File, table *DiskTable) string {
+	layout := "Distribución del disco:\n"
+	line := func(indent string, label string, start int64, size int64) {
+		layout += fmt.Sprintf("%s%s: inicio %d, tamaño %d bytes\n", indent, label, start, size)
+	}
 
-	// Verificar si ya se ha montado alguna partición de este disco
-	mountedPartitionsInDisk := mountedPartitions[diskID]
-	var letter byte
+	var used []Gap
+	partitions := make([]Structs.Partition, 0, 4)
+	for _, partition := range table.Partitions {
+		if partition.Size > 0 {
+			partitions = append(partitions, partition)
+			used = append(used, Gap{Start: partition.Start, Size: partition.Size})
+		}
+	}
+	free := table.Gaps()
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].Start < partitions[j].Start })
 
-	if len(mountedPartitionsInDisk) == 0 {
-		// Es un nuevo disco, asignar la siguiente letra disponible
-		if len(mountedPartitions) == 0 {
-			letter = 'a'
+	// Cada espacio libre se lista justo antes de la primera partición que está después de él
+	nextFree := 0
+	for _, partition := range partitions {
+		for ; nextFree < len(free) && free[nextFree].Start < partition.Start; nextFree++ {
+			line("  ", "Libre", free[nextFree].Start, free[nextFree].Size)
+		}
+		name := strings.TrimRight(string(partition.Name[:]), "\x00")
+		line("  ", fmt.Sprintf("%s (%c)", name, partition.Type[0]), partition.Start, partition.Size)
+
+		if partition.Type[0] == 'e' {
+			chain, _ := readEBRChain(file, partition)
+			for _, entry := range chain {
+				if entry.EBR.PartSize > 0 {
+					line("    ", fmt.Sprintf("%s (l)", strings.TrimRight(string(entry.EBR.PartName[:]), "\x00")), entry.EBR.PartStart, entry.EBR.PartSize)
+				}
+			}
+			for _, gap := range logicalGaps(partition, chain) {
+				line("    ", "Libre", gap.Start, gap.Size)
+			}
+		}
+	}
+	for ; nextFree < len(free); nextFree++ {
+		line("  ", "Libre", free[nextFree].Start, free[nextFree].Size)
+	}
+	return layout
+}
+
+// ResizePartition cambia el tamaño de la partición name en delta bytes sin moverla de su inicio.
+// Para crecer solo usa el espacio libre que está justo después de la partición; al reducirse no puede
+// quedar en cero ni cortar sus particiones lógicas o las estructuras de su sistema de archivos.
+func ResizePartition(path string, name string, delta int64) (string, error) {
+	var logs string
+	logs += "======Start FDISK ADD======\n"
+	logs += fmt.Sprintf("Path: %s\n", path)
+	logs += fmt.Sprintf("Name: %s\n", name)
+	logs += fmt.Sprintf("Add: %d bytes\n", delta)
+
+	file, err := Utilities.OpenFile(path)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error: Could not open file at path: %s", path)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	defer file.Close()
+
+	table, err := ReadTable(file)
+	if err != nil {
+		errMsg := "Error: Could not read MBR from file"
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+
+	for i := range table.Partitions {
+		partition := table.Partitions[i]
+		if partition.Size == 0 || strings.TrimRight(string(partition.Name[:]), "\x00") != name {
+			continue
+		}
+
+		// La extendida no puede cortar su primer EBR ni sus lógicas; las demás, su sistema de archivos
+		var minEnd int64
+		if partition.Type[0] == 'e' {
+			chain, err := readEBRChain(file, partition)
+			if err != nil {
+				errMsg := fmt.Sprintf("Error: %v", err)
+				logs += errMsg + "\n"
+				return logs, fmt.Errorf(errMsg)
+			}
+			minEnd = partition.Start + EBRSize(table.MBR.Version)
+			for _, entry := range chain {
+				minEnd = max(minEnd, entry.EBR.PartStart+entry.EBR.PartSize)
+			}
 		} else {
-			lastDiskID := getLastDiskID()
-			lastLetter := mountedPartitions[lastDiskID][0].ID[len(mountedPartitions[lastDiskID][0].ID)-1]
-			letter = lastLetter + 1
+			minEnd = FilesystemEnd(file, partition.Start)
 		}
-	} else {
-		// Utilizar la misma letra que las otras particiones montadas en el mismo disco
-		letter = mountedPartitionsInDisk[0].ID[len(mountedPartitionsInDisk[0].ID)-1]
+
+		newSize, err := CheckResize(name, partition.Start, partition.Size, delta, minEnd, table.Gaps())
+		if err != nil {
+			errMsg := fmt.Sprintf("Error: %v", err)
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+
+		table.Partitions[i].Size = newSize
+		if err := WriteTable(file, table); err != nil {
+			errMsg := "Error: Could not write MBR to file"
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+
+		logs += describeLayout(file, table)
+		logs += "======FIN FDISK ADD======\n"
+		return logs + fmt.Sprintf("FDISK: Partición %s redimensionada de %d a %d bytes en: %s", name, partition.Size, newSize, path), nil
+	}
+
+	for i := range table.Partitions {
+		extended := table.Partitions[i]
+		if extended.Size == 0 || extended.Type[0] != 'e' {
+			continue
+		}
+
+		chain, err := readEBRChain(file, extended)
+		if err != nil {
+			errMsg := fmt.Sprintf("Error: %v", err)
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+		for _, entry := range chain {
+			if entry.EBR.PartSize == 0 || strings.TrimRight(string(entry.EBR.PartName[:]), "\x00") != name {
+				continue
+			}
+
+			minEnd := FilesystemEnd(file, entry.EBR.PartStart)
+			newSize, err := CheckResize(name, entry.EBR.PartStart, entry.EBR.PartSize, delta, minEnd, logicalGaps(extended, chain))
+			if err != nil {
+				errMsg := fmt.Sprintf("Error: %v", err)
+				logs += errMsg + "\n"
+				return logs, fmt.Errorf(errMsg)
+			}
+
+			oldSize := entry.EBR.PartSize
+			entry.EBR.PartSize = newSize
+			if err := WriteEBR(file, entry.EBR, entry.Position); err != nil {
+				errMsg := fmt.Sprintf("Error: No se pudo actualizar el EBR en la posición %d", entry.Position)
+				logs += errMsg + "\n"
+				return logs, fmt.Errorf(errMsg)
+			}
+
+			logs += describeLayout(file, table)
+			logs += "======FIN FDISK ADD======\n"
+			return logs + fmt.Sprintf("FDISK: Partición lógica %s redimensionada de %d a %d bytes en: %s", name, oldSize, newSize, path), nil
+		}
+	}
+
+	errMsg := fmt.Sprintf("Error: No existe la partición %s en %s", name, path)
+	logs += errMsg + "\n"
+	return logs, fmt.Errorf(errMsg)
+}
+
+func Mount(path string, name string) (string, error) {
+	file, err := Utilities.OpenFile(path)
+	if err != nil {
+		return "", fmt.Errorf("no se pudo abrir el archivo en la ruta: %s", path)
+	}
+	defer file.Close()
+
+	table, err := ReadTable(file)
+	if err != nil {
+		return "", fmt.Errorf("no se pudo leer el MBR desde el archivo: %v", err)
+	}
+
+	fmt.Printf("Buscando partición con nombre: '%s'\n", name)
+
+	// Se busca entre las primarias y las lógicas de la extendida
+	ref, err := findPartition(file, table, byName(name))
+	if err != nil {
+		return "", fmt.Errorf("no se pudo leer la partición extendida: %v", err)
+	}
+	if ref == nil {
+		return "", fmt.Errorf("partición %s no encontrada o es una partición extendida", name)
+	}
+	partition := ref.Partition
+
+	// Verificar si la partición ya está montada. Si el MBR dice que sí pero no está en la tabla,
+	// quedó así de una ejecución anterior que no la desmontó y se puede volver a montar.
+	var notice string
+	if partition.Status[0] == '1' {
+		if IsMounted(path, name) {
+			return "", fmt.Errorf("la partición %s ya está montada", name)
+		}
+		notice = fmt.Sprintf("La partición %s estaba marcada como montada sin estar en la tabla de montajes; se vuelve a montar.\n", name)
 	}
 
-	// Incrementar el número para esta partición
-	carnet := "202212209"
-	lastTwoDigits := carnet[len(carnet)-2:]
-	partitionID := fmt.Sprintf("%s%d%c", lastTwoDigits, partitionIndex+1, letter)
+	// Generar el ID de la partición
+	diskID := generateDiskID(path)
+	// El ID tiene que caber en el campo Id de la partición o en el PartId de su EBR
+	partitionID, err := NextMountID(idAssignments, mountedPartitions, path, name, ref.Number, MountIDLength(table.MBR.Version, ref.Slot < 0))
+	if err != nil {
+		return "", err
+	}
 
-	// Actualizar el estado de la partición a montada y asignar el ID
-	partition.Status[0] = '1'
-	copy(partition.Id[:], partitionID)
-	TempMBR.Partitions[partitionIndex] = partition
+	// Actualizar el estado de la partición a montada y asignar el ID (en el MBR o en su EBR)
+	if err := ref.setMountState(file, table, '1', partitionID); err != nil {
+		return "", fmt.Errorf("no se pudo guardar el estado de montaje en el disco: %v", err)
+	}
 	mountedPartitions[diskID] = append(mountedPartitions[diskID], MountedPartition{
 		Path:   path,
 		Name:   name,
@@ -536,23 +1055,132 @@ func Mount(path string, name string) (string, error) {
 		Status: '1',
 	})
 
-	// Escribir el MBR actualizado al archivo
-	if err := Utilities.WriteObject(file, TempMBR, 0); err != nil {
-		mountedPartitionsStr := GetMountedPartitionsString()
-		return "Error: No se pudo sobrescribir el MBR en el archivo:\n" + mountedPartitionsStr, nil
+	// Si la partición está formateada se registra el montaje en su superbloque
+	warning, err := recordMount(file, partition.Start)
+	if err != nil {
+		return "", err
+	}
+	if warning != "" {
+		mounts := mountedPartitions[diskID]
+		mounts[len(mounts)-1].Unclean = true
+		warning = fmt.Sprintf("Advertencia: la partición %s %s; se recomienda revisar su consistencia.\n", name, warning)
 	}
+	saveMounts()
 
 	mountedPartitionsStr := GetMountedPartitionsString()
-	return fmt.Sprintf("Partición montada con ID: %s\n%s", partitionID, mountedPartitionsStr), nil
+	return fmt.Sprintf("%s%sPartición montada con ID: %s\n%s", notice, warning, partitionID, mountedPartitionsStr), nil
+}
+
+// Formato de las fechas de montaje y desmontaje que se guardan en el superbloque;
+// incluye los segundos y ocupa exactamente los 17 bytes del campo
+const superblockTimeFormat = "02/01/06 15:04:05"
+
+// parseSuperblockTime interpreta una fecha del superbloque; Mkfs guarda solo el día
+func parseSuperblockTime(value [17]byte) (time.Time, bool) {
+	text := strings.TrimRight(string(value[:]), "\x00")
+	for _, format := range []string{superblockTimeFormat, "02/01/2006"} {
+		if t, err := time.ParseInLocation(format, text, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// recordMount incrementa S_mnt_count y guarda S_mtime si en start hay un EXT2. Si el montaje anterior
+// no tiene un S_umtime igual o posterior (no se desmontó), devuelve la advertencia de montaje sucio.
+func recordMount(file *os.File, start int64) (string, error) {
+	superblock, err := ReadSuperblock(file, start)
+	if err != nil || superblock.S_magic != 0xEF53 {
+		return "", nil
+	}
+
+	var warning string
+	mtime, okMount := parseSuperblockTime(superblock.S_mtime)
+	umtime, okUnmount := parseSuperblockTime(superblock.S_umtime)
+	if okMount && (!okUnmount || umtime.Before(mtime)) {
+		warning = fmt.Sprintf("no se desmontó desde su último montaje (%s)", strings.TrimRight(string(superblock.S_mtime[:]), "\x00"))
+	}
+
+	superblock.S_mnt_count++
+	superblock.S_mtime = [17]byte{}
+	copy(superblock.S_mtime[:], time.Now().Format(superblockTimeFormat))
+	if err := WriteSuperblock(file, superblock, start); err != nil {
+		return "", fmt.Errorf("no se pudo actualizar el superbloque: %v", err)
+	}
+	return warning, nil
 }
 
-// Función para obtener el ID del último disco montado
-func getLastDiskID() string {
-	var lastDiskID string
-	for diskID := range mountedPartitions {
-		lastDiskID = diskID
+// Unmount desmonta la partición con el ID indicado: la quita de la tabla de montajes, limpia su estado
+// e ID en el MBR y, si tiene un sistema de archivos, guarda la fecha de desmontaje en el superbloque
+func Unmount(id string) (string, error) {
+	var logs string
+	logs += "======Start UNMOUNT======\n"
+	logs += fmt.Sprintf("ID: %s\n", id)
+
+	var mounted MountedPartition
+	var diskID string
+	index := -1
+	for disk, partitions := range mountedPartitions {
+		for i, partition := range partitions {
+			if SameID(partition.ID, id) {
+				mounted, diskID, index = partition, disk, i
+			}
+		}
 	}
-	return lastDiskID
+	if index == -1 {
+		errMsg := fmt.Sprintf("Error: No hay ninguna partición montada con el ID %s", id)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+
+	file, err := Utilities.OpenFile(mounted.Path)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error: Could not open file at path: %s", mounted.Path)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	defer file.Close()
+
+	table, err := ReadTable(file)
+	if err != nil {
+		errMsg := "Error: Could not read MBR from file"
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+
+	// Si la partición ya no está en el disco solo se quita de la tabla
+	ref, _ := findPartition(file, table, byName(mounted.Name))
+	if ref != nil {
+		partition := ref.Partition
+		if err := ref.setMountState(file, table, '0', ""); err != nil {
+			errMsg := "Error: No se pudo guardar el estado de montaje en el disco"
+			logs += errMsg + "\n"
+			return logs, fmt.Errorf(errMsg)
+		}
+
+		if superblock, err := ReadSuperblock(file, partition.Start); err == nil && superblock.S_magic == 0xEF53 {
+			superblock.S_umtime = [17]byte{}
+			copy(superblock.S_umtime[:], time.Now().Format(superblockTimeFormat))
+			if err := WriteSuperblock(file, superblock, partition.Start); err != nil {
+				errMsg := "Error: No se pudo actualizar el superbloque"
+				logs += errMsg + "\n"
+				return logs, fmt.Errorf(errMsg)
+			}
+			logs += fmt.Sprintf("S_umtime: %s\n", strings.TrimRight(string(superblock.S_umtime[:]), "\x00"))
+		}
+	}
+
+	if mounted.LoggedIn {
+		logs += "Se cerró la sesión activa en la partición\n"
+	}
+	mountedPartitions[diskID] = append(mountedPartitions[diskID][:index], mountedPartitions[diskID][index+1:]...)
+	if len(mountedPartitions[diskID]) == 0 {
+		delete(mountedPartitions, diskID)
+	}
+	saveMounts()
+
+	logs += "======FIN UNMOUNT======\n"
+	return logs + fmt.Sprintf("UNMOUNT: Partición %s (%s) desmontada de: %s", mounted.Name, id, mounted.Path), nil
 }
 
 func generateDiskID(path string) string {
@@ -562,7 +1190,7 @@ func generateDiskID(path string) string {
 func GetPartitionByID(id string) *MountedPartition {
 	for _, partitions := range mountedPartitions {
 		for _, partition := range partitions {
-			if partition.ID == id {
+			if SameID(partition.ID, id) {
 				return &partition
 			}
 		}
@@ -589,24 +1217,42 @@ func GenerateMBRReport(path string, partition MountedPartition) error {
 	}
 	defer file.Close()
 
-	var mbr Structs.MBR
-	if err := binary.Read(file, binary.LittleEndian, &mbr); err != nil {
+	table, err := ReadTable(file)
+	if err != nil {
 		return fmt.Errorf("error al leer el MBR desde el archivo: %v", err)
 	}
+	mbr := table.MBR
 
 	// Crear el contenido DOT con una tabla
 	dotContent := fmt.Sprintf(`digraph G {
         node [shape=plaintext]
         tabla [label=<
             <table border="0" cellborder="1" cellspacing="0">
-                <tr><td colspan="2" bgcolor="lightblue"> REPORTE MBR </td></tr>
+                <tr><td colspan="2" bgcolor="lightblue"> REPORTE %s </td></tr>
                 <tr><td bgcolor="lightgrey">mbr_tamano</td><td>%d</td></tr>
                 <tr><td bgcolor="lightgrey">mrb_fecha_creacion</td><td>%s</td></tr>
                 <tr><td bgcolor="lightgrey">mbr_disk_signature</td><td>%d</td></tr>
-            `, mbr.MbrSize, string(mbr.CreationDate[:]), mbr.Signature)
+            `, strings.ToUpper(table.Kind()), mbr.MbrSize, string(mbr.CreationDate[:]), mbr.Signature)
 
-	// Iterar sobre todas las particiones y mostrar sus datos, incluso si no están definidas
-	for i, part := range mbr.Partitions {
+	// En una GPT se muestran los datos de sus encabezados
+	if gpt := table.GPT; gpt != nil {
+		dotContent += fmt.Sprintf(`
+                <tr><td bgcolor="lightgrey">gpt_disk_guid</td><td>%s</td></tr>
+                <tr><td bgcolor="lightgrey">gpt_header_crc32</td><td>%08x</td></tr>
+                <tr><td bgcolor="lightgrey">gpt_entries_crc32</td><td>%08x</td></tr>
+                <tr><td bgcolor="lightgrey">gpt_entries</td><td>%d</td></tr>
+                <tr><td bgcolor="lightgrey">gpt_first_usable</td><td>%d</td></tr>
+                <tr><td bgcolor="lightgrey">gpt_last_usable</td><td>%d</td></tr>
+                <tr><td bgcolor="lightgrey">gpt_backup_header</td><td>%d</td></tr>
+            `, FormatGUID(gpt.DiskGUID), gpt.HeaderCRC, gpt.EntriesCRC, gpt.EntryCount, gpt.FirstUsable, gpt.LastUsable, gpt.AltPosition)
+	}
+
+	// Iterar sobre todas las particiones y mostrar sus datos, incluso si no están definidas;
+	// de una GPT solo las entradas usadas, que pueden ser unas pocas entre muchas
+	for i, part := range table.Partitions {
+		if table.GPT != nil && part.Size == 0 {
+			continue
+		}
 		// Convertir los valores a caracteres o mostrar un valor predeterminado si están vacíos
 		partStatus := '0'
 		if part.Status[0] != 0 {
@@ -643,6 +1289,11 @@ func GenerateMBRReport(path string, partition MountedPartition) error {
                 <tr><td bgcolor="lightgrey">part_size</td><td>%d</td></tr>
                 <tr><td bgcolor="lightgrey">part_name</td><td>%s</td></tr>
             `, bgColor, i+1, partStatus, partType, partFit, part.Start, part.Size, partName)
+		if table.GPT != nil {
+			dotContent += fmt.Sprintf(`
+                <tr><td bgcolor="lightgrey">part_guid</td><td>%s</td></tr>
+            `, FormatGUID(table.GUID(i)))
+		}
 
 		// Si la partición es extendida, buscar EBRs y mostrar particiones lógicas
 		if partType == 'e' {
@@ -682,13 +1333,12 @@ func GenerateMBRReport(path string, partition MountedPartition) error {
 }
 
 // showLogicalPartitions muestra las particiones lógicas dentro de una partición extendida
-func showLogicalPartitions(file *os.File, extendedStart int32, dotContent *string) error {
-	var ebr Structs.EBR
+func showLogicalPartitions(file *os.File, extendedStart int64, dotContent *string) error {
 	ebrPosition := extendedStart
 
 	for {
 		// Leer el EBR en la posición actual
-		err := readEBR(file, &ebr, ebrPosition)
+		ebr, err := ReadEBR(file, ebrPosition)
 		if err != nil {
 			return fmt.Errorf("error al leer EBR: %v", err)
 		}
@@ -718,26 +1368,6 @@ func showLogicalPartitions(file *os.File, extendedStart int32, dotContent *strin
 	return nil
 }
 
-// Función para leer un EBR desde una posición específica en el archivo
-func readEBR(file *os.File, ebr *Structs.EBR, position int32) error {
-	// Crear un buffer para leer los datos del EBR
-	buffer := make([]byte, binary.Size(*ebr))
-
-	// Leer los bytes del EBR desde la posición especificada
-	_, err := file.ReadAt(buffer, int64(position))
-	if err != nil {
-		return err
-	}
-
-	// Decodificar los datos del buffer al EBR utilizando LittleEndian
-	err = binary.Read(strings.NewReader(string(buffer)), binary.LittleEndian, ebr)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
 func createDirectoryIfNotExists(path string) error {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return os.MkdirAll(path, os.ModePerm)
@@ -754,6 +1384,12 @@ func getFileNames(path string) (string, string) {
 	return dotFileName, outputImage
 }
 
+// ReportImagePath devuelve la ruta de la imagen que generan los reportes Graphviz para la ruta indicada
+func ReportImagePath(path string) string {
+	_, outputImage := getFileNames(path)
+	return filepath.Join(path, outputImage)
+}
+
 // GenerateDiskReport genera un reporte de la estructura de particiones del disco y lo guarda en la ruta especificada
 func GenerateDiskReport(path string, partition *MountedPartition) error {
 	// Crear las carpetas padre si no existen
@@ -772,10 +1408,11 @@ func GenerateDiskReport(path string, partition *MountedPartition) error {
 	}
 	defer file.Close()
 
-	var mbr Structs.MBR
-	if err := binary.Read(file, binary.LittleEndian, &mbr); err != nil {
+	table, err := ReadTable(file)
+	if err != nil {
 		return fmt.Errorf("error al leer el MBR desde el archivo: %v", err)
 	}
+	mbr := table.MBR
 
 	// Crear el contenido DOT inicial con la estructura requerida
 	dotContent := `digraph G {
@@ -789,26 +1426,35 @@ func GenerateDiskReport(path string, partition *MountedPartition) error {
 				<TABLE BORDER="1" CELLBORDER="1" CELLSPACING="0" COLOR="blue">
 					<TR>`
 
-	// Variables para calcular el espacio total y usado
-	var usedSpace int32
 	var extendedPartition *Structs.Partition
-	const mbrSize int32 = 159 // Tamaño fijo de la estructura MRB
 
-	// Añadir MBR
-	dotContent += fmt.Sprintf(`<TD BGCOLOR="lightblue">MBR</TD>`)
-	usedSpace += mbrSize
-	// Iterar sobre todas las particiones
-	for i, part := range mbr.Partitions {
+	// Añadir MBR (o la GPT principal con sus entradas)
+	dotContent += fmt.Sprintf(`<TD BGCOLOR="lightblue">%s</TD>`, strings.ToUpper(table.Kind()))
+
+	// Recorrer las particiones en el orden en que están en el disco (no en el de los slots de la tabla)
+	order := make([]int, len(table.Partitions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return table.Partitions[order[a]].Start < table.Partitions[order[b]].Start })
+	position := table.DataStart()
+	for _, i := range order {
+		part := table.Partitions[i]
 		if part.Size > 0 {
-			usedSpace += part.Size
+			// Espacio libre antes de la partición
+			if part.Start > position {
+				freePercentage := float64(part.Start-position) / float64(mbr.MbrSize) * 100
+				dotContent += fmt.Sprintf(`<TD BGCOLOR="lightgray">Libre<BR/>%.2f%%</TD>`, freePercentage)
+			}
+			position = part.Start + part.Size
 			percentage := float64(part.Size) / float64(mbr.MbrSize) * 100
 			partType := rune(part.Type[0])
 			partName := strings.TrimRight(string(part.Name[:]), "\x00")
 
 			if partType == 'e' {
-				extendedPartition = &mbr.Partitions[i]
+				extendedPartition = &table.Partitions[i]
 				dotContent += fmt.Sprintf(`<TD><TABLE BORDER="0" CELLBORDER="1" CELLSPACING="0"><TR><TD COLSPAN="5" BGCOLOR="lightgreen">Extendida %.2f%%</TD></TR><TR>`, percentage)
-				err = addLogicalPartitions(file, extendedPartition.Start, extendedPartition.Size, &dotContent)
+				err = addLogicalPartitions(file, extendedPartition.Start, extendedPartition.Size, EBRSize(mbr.Version), &dotContent)
 				if err != nil {
 					return fmt.Errorf("error al mostrar particiones lógicas: %v", err)
 				}
@@ -820,12 +1466,17 @@ func GenerateDiskReport(path string, partition *MountedPartition) error {
 	}
 
 	// Calcular y mostrar el espacio libre al final
-	freeSpace := mbr.MbrSize - usedSpace
+	freeSpace := table.DataEnd() - position
 	if freeSpace > 0 {
 		freePercentage := float64(freeSpace) / float64(mbr.MbrSize) * 100
 		dotContent += fmt.Sprintf(`<TD BGCOLOR="lightgray">Libre<BR/>%.2f%%</TD>`, freePercentage)
 	}
 
+	// La GPT termina con la copia de sus entradas y el encabezado de respaldo
+	if table.GPT != nil {
+		dotContent += `<TD BGCOLOR="lightblue">GPT respaldo</TD>`
+	}
+
 	// Cerrar la tabla y el contenido DOT
 	dotContent += `
 					</TR>
@@ -857,14 +1508,13 @@ func GenerateDiskReport(path string, partition *MountedPartition) error {
 	return nil
 }
 
-func addLogicalPartitions(file *os.File, extendedStart int32, extendedSize int32, dotContent *string) error {
-	var ebr Structs.EBR
+func addLogicalPartitions(file *os.File, extendedStart int64, extendedSize int64, ebrSize int64, dotContent *string) error {
 	ebrPosition := extendedStart
 	remainingSize := extendedSize
 
 	for {
 		// Leer el EBR en la posición actual
-		err := readEBR(file, &ebr, ebrPosition)
+		ebr, err := ReadEBR(file, ebrPosition)
 		if err != nil {
 			return fmt.Errorf("error al leer EBR: %v", err)
 		}
@@ -890,7 +1540,7 @@ func addLogicalPartitions(file *os.File, extendedStart int32, extendedSize int32
 		}
 
 		// Calcular y mostrar el espacio libre entre particiones lógicas
-		freeSpace := ebr.PartNext - (ebrPosition + int32(unsafe.Sizeof(ebr)) + ebr.PartSize)
+		freeSpace := ebr.PartNext - (ebrPosition + ebrSize + ebr.PartSize)
 		if freeSpace > 0 {
 			freePercentage := float64(freeSpace) / float64(extendedSize) * 100
 			*dotContent += fmt.Sprintf(`<TD BGCOLOR="lightgray">Libre<BR/>%.2f%%</TD>`, freePercentage)
@@ -920,10 +1570,15 @@ func GenerateInodeReport(path string, partition *MountedPartition) error {
 	}
 	defer file.Close()
 
+	// Buscar la partición (primaria o lógica) dentro del disco
+	partitionData, err := LocatePartition(file, partition.ID)
+	if err != nil {
+		return fmt.Errorf("error al encontrar la partición: %v", err)
+	}
+
 	// Leer el Superblock para obtener la información de los inodos
-	var superblock Structs.Superblock
-	superblockOffset := int64(binary.Size(Structs.MBR{})) // Ajusta según la posición real del Superblock
-	if err := Utilities.ReadObject(file, &superblock, superblockOffset); err != nil {
+	superblock, err := ReadSuperblock(file, partitionData.Start)
+	if err != nil {
 		return fmt.Errorf("error al leer el Superblock: %v", err)
 	}
 
@@ -936,8 +1591,8 @@ func GenerateInodeReport(path string, partition *MountedPartition) error {
 	// Iterar sobre cada inodo y generar su representación en Graphviz
 	for i := int32(0); i < superblock.S_inodes_count; i++ {
 		var inode Structs.Inode
-		inodeOffset := superblock.S_inode_start + i*superblock.S_inode_size
-		if err := Utilities.ReadObject(file, &inode, int64(inodeOffset)); err != nil {
+		inodeOffset := superblock.S_inode_start + int64(i)*int64(superblock.S_inode_size)
+		if err := Utilities.ReadObject(file, &inode, inodeOffset); err != nil {
 			return fmt.Errorf("error al leer inodo %d: %v", i, err)
 		}
 
@@ -1074,10 +1729,15 @@ func GenerateBlockReport(path string, partition *MountedPartition) error {
 	}
 	defer file.Close()
 
+	// Buscar la partición (primaria o lógica) dentro del disco
+	partitionData, err := LocatePartition(file, partition.ID)
+	if err != nil {
+		return fmt.Errorf("Error al encontrar la partición: %v", err)
+	}
+
 	// Leer el Superblock para obtener la información de los bloques
-	var superblock Structs.Superblock
-	superblockOffset := int64(binary.Size(Structs.MBR{})) // Ajusta según la posición real del Superblock
-	if err := Utilities.ReadObject(file, &superblock, superblockOffset); err != nil {
+	superblock, err := ReadSuperblock(file, partitionData.Start)
+	if err != nil {
 		return fmt.Errorf("Error al leer el Superblock: %v", err)
 	}
 
@@ -1093,8 +1753,8 @@ func GenerateBlockReport(path string, partition *MountedPartition) error {
 	// Iterar sobre cada bloque y generar su representación en Graphviz
 	for i := int32(0); i < superblock.S_blocks_count; i++ {
 		var fileBlock Structs.Fileblock
-		blockOffset := superblock.S_block_start + i*superblock.S_block_size
-		if err := Utilities.ReadObject(file, &fileBlock, int64(blockOffset)); err != nil {
+		blockOffset := superblock.S_block_start + int64(i)*int64(superblock.S_block_size)
+		if err := Utilities.ReadObject(file, &fileBlock, blockOffset); err != nil {
 			return fmt.Errorf("Error al leer bloque %d: %v", i, err)
 		}
 
@@ -1181,32 +1841,15 @@ func GenerateSuperblockReport(path string, partition *MountedPartition) error {
 	}
 	defer file.Close()
 
-	// Leer el MBR para ubicar la partición
-	var TempMBR Structs.MBR
-	if err := Utilities.ReadObject(file, &TempMBR, 0); err != nil {
-		return fmt.Errorf("Error al leer el MBR del archivo: %v", err)
-	}
-
-	// Buscar la partición correspondiente dentro del MBR
-	var partitionData Structs.Partition
-	partitionFound := false
-	for i := 0; i < 4; i++ {
-		if string(TempMBR.Partitions[i].Id[:]) == partition.ID {
-			partitionData = TempMBR.Partitions[i]
-			partitionFound = true
-			break
-		}
-	}
-
-	// Si no se encuentra la partición dentro del MBR, retornar un error
-	if !partitionFound {
-		return fmt.Errorf("Error: Partición no encontrada dentro del MBR")
+	// Buscar la partición (primaria o lógica) dentro del disco
+	partitionData, err := LocatePartition(file, partition.ID)
+	if err != nil {
+		return fmt.Errorf("Error: Partición no encontrada dentro del disco: %v", err)
 	}
 
 	// Leer el Superblock de la partición
-	var superblock Structs.Superblock
-	superblockOffset := int64(partitionData.Start)
-	if err := Utilities.ReadObject(file, &superblock, superblockOffset); err != nil {
+	superblock, err := ReadSuperblock(file, partitionData.Start)
+	if err != nil {
 		return fmt.Errorf("Error al leer el Superblock desde el archivo: %v", err)
 	}
 
@@ -1278,7 +1921,7 @@ func GetMountedPartitionSuperblock(id string) (*Structs.Superblock, *MountedPart
 	// Buscar en el mapa de particiones montadas
 	for _, partitions := range mountedPartitions {
 		for _, partition := range partitions {
-			if partition.ID == id {
+			if SameID(partition.ID, id) {
 				mountedPartition = &partition
 				partitionFound = true
 				break
@@ -1301,32 +1944,15 @@ func GetMountedPartitionSuperblock(id string) (*Structs.Superblock, *MountedPart
 	}
 	defer file.Close()
 
-	// Leer el MBR para ubicar la partición
-	var TempMBR Structs.MBR
-	if err := Utilities.ReadObject(file, &TempMBR, 0); err != nil {
-		return nil, nil, "", fmt.Errorf("Error al leer el MBR del archivo: %v", err)
-	}
-
-	// Buscar la partición correspondiente dentro del MBR
-	var partition Structs.Partition
-	partitionFound = false
-	for i := 0; i < 4; i++ {
-		if string(TempMBR.Partitions[i].Id[:]) == mountedPartition.ID {
-			partition = TempMBR.Partitions[i]
-			partitionFound = true
-			break
-		}
-	}
-
-	// Si no se encuentra la partición dentro del MBR, retornar un error
-	if !partitionFound {
-		return nil, nil, "", fmt.Errorf("Error: Partición no encontrada dentro del MBR")
+	// Buscar la partición (primaria o lógica) dentro del disco
+	partition, err := LocatePartition(file, mountedPartition.ID)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("Error: Partición no encontrada dentro del disco: %v", err)
 	}
 
 	// Leer el Superblock de la partición
-	var superblock Structs.Superblock
-	superblockOffset := int64(partition.Start)
-	if err := Utilities.ReadObject(file, &superblock, superblockOffset); err != nil {
+	superblock, err := ReadSuperblock(file, partition.Start)
+	if err != nil {
 		return nil, nil, "", fmt.Errorf("Error al leer el Superblock desde el archivo: %v", err)
 	}
 
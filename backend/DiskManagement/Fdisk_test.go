@@ -0,0 +1,228 @@
+package DiskManagement
+
+import (
+	"backend/Structs"
+	"bytes"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// partitionsOf devuelve las particiones del disco por nombre, incluidas la extendida y sus lógicas
+// (como logicalView), junto con los nombres de las lógicas en el orden de la cadena de EBR
+func partitionsOf(t *testing.T, path string) (map[string]Structs.Partition, []string) {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	table, err := ReadTable(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	partitions := make(map[string]Structs.Partition)
+	var logicals []string
+	for _, partition := range table.Partitions {
+		if partition.Size == 0 {
+			continue
+		}
+		partitions[strings.TrimRight(string(partition.Name[:]), "\x00")] = partition
+		if partition.Type[0] != 'e' {
+			continue
+		}
+		chain, err := readEBRChain(file, partition)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range chain {
+			if entry.EBR.PartSize > 0 {
+				name := strings.TrimRight(string(entry.EBR.PartName[:]), "\x00")
+				partitions[name] = logicalView(entry.EBR)
+				logicals = append(logicals, name)
+			}
+		}
+	}
+	return partitions, logicals
+}
+
+// names devuelve los nombres de las particiones ordenados
+func names(partitions map[string]Structs.Partition) string {
+	var list []string
+	for name := range partitions {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return strings.Join(list, " ")
+}
+
+// TestFdiskCorrelative revisa que una partición creada después de eliminar otra no repita el
+// correlativo de una que sigue en el disco
+func TestFdiskCorrelative(t *testing.T) {
+	path := newTestDisk(t, "64", testPartition{"p1", "p", 100}, testPartition{"p2", "p", 100}, testPartition{"p3", "p", 100})
+	if _, err := DeletePartition(path, "p2", "fast"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Fdisk(100, path, "p4", "k", "p", "w"); err != nil {
+		t.Fatal(err)
+	}
+
+	partitions, _ := partitionsOf(t, path)
+	seen := make(map[int32]string)
+	for name, partition := range partitions {
+		if other, ok := seen[partition.Correlative]; ok {
+			t.Errorf("%s y %s tienen el correlativo %d", name, other, partition.Correlative)
+		}
+		seen[partition.Correlative] = name
+	}
+	if got := partitions["p4"].Correlative; got != 4 {
+		t.Errorf("correlativo de p4 = %d, se esperaba 4", got)
+	}
+}
+
+func TestDeletePartition(t *testing.T) {
+	tests := []struct {
+		name     string
+		delete   string
+		mode     string
+		want     string // Particiones que quedan
+		logicals string // Lógicas que quedan, en el orden de la cadena
+	}{
+		{"primaria", "p1", "fast", "ext l1 l2 l3 p2", "l1 l2 l3"},
+		{"primaria con ceros", "p2", "full", "ext l1 l2 l3 p1", "l1 l2 l3"},
+		{"extendida con sus lógicas", "ext", "fast", "p1 p2", ""},
+		{"primera lógica", "l1", "fast", "ext l2 l3 p1 p2", "l2 l3"},
+		{"lógica del medio", "l2", "full", "ext l1 l3 p1 p2", "l1 l3"},
+		{"última lógica", "l3", "fast", "ext l1 l2 p1 p2", "l1 l2"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resetMounts(t)
+			path := newTestDisk(t, "32", testPartition{"p1", "p", 100}, testPartition{"ext", "e", 500}, testPartition{"l1", "l", 100},
+				testPartition{"l2", "l", 100}, testPartition{"l3", "l", 100}, testPartition{"p2", "p", 100})
+			before, _ := partitionsOf(t, path)
+			deleted := before[test.delete]
+
+			// Se marca el inicio de la partición para ver si -delete=full lo llena con ceros
+			file, err := os.OpenFile(path, os.O_RDWR, 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			marker := []byte("datos")
+			if deleted.Type[0] != 'e' {
+				if _, err := file.WriteAt(marker, deleted.Start); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if _, err := DeletePartition(path, test.delete, test.mode); err != nil {
+				t.Fatal(err)
+			}
+			after, logicals := partitionsOf(t, path)
+			if got := names(after); got != test.want {
+				t.Errorf("particiones después de eliminar: %s, se esperaba %s", got, test.want)
+			}
+			if got := strings.Join(logicals, " "); got != test.logicals {
+				t.Errorf("cadena de EBR después de eliminar: %s, se esperaba %s", got, test.logicals)
+			}
+			for name, partition := range after {
+				if partition.Start != before[name].Start || partition.Size != before[name].Size {
+					t.Errorf("%s se movió de %d (%d bytes) a %d (%d bytes)", name, before[name].Start, before[name].Size, partition.Start, partition.Size)
+				}
+			}
+
+			if deleted.Type[0] != 'e' {
+				data := make([]byte, len(marker))
+				if _, err := file.ReadAt(data, deleted.Start); err != nil {
+					t.Fatal(err)
+				}
+				if zeroed := bytes.Equal(data, make([]byte, len(marker))); zeroed != (test.mode == "full") {
+					t.Errorf("con -delete=%s el inicio de la partición quedó con %q", test.mode, data)
+				}
+			}
+			file.Close()
+
+			// El espacio liberado se puede volver a usar
+			type_ := "p"
+			if deleted.Type[0] == 'l' {
+				type_ = "l"
+			}
+			if deleted.Type[0] != 'e' {
+				if _, err := Fdisk(100, path, "nueva", "k", type_, "w"); err != nil {
+					t.Errorf("no se pudo crear una partición en el espacio liberado: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteMountedPartition(t *testing.T) {
+	resetMounts(t)
+	path := newTestDisk(t, "64", testPartition{"p1", "p", 100}, testPartition{"ext", "e", 400}, testPartition{"l1", "l", 100})
+	for _, name := range []string{"p1", "l1"} {
+		if _, err := Mount(path, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"p1", "l1", "ext"} {
+		if _, err := DeletePartition(path, name, "fast"); err == nil || !strings.Contains(err.Error(), "montada") {
+			t.Errorf("eliminar %s montada: %v", name, err)
+		}
+	}
+	if partitions, _ := partitionsOf(t, path); names(partitions) != "ext l1 p1" {
+		t.Errorf("después de los intentos quedan %s", names(partitions))
+	}
+}
+
+func TestResizePartition(t *testing.T) {
+	const kb = 1024
+	tests := []struct {
+		name    string
+		target  string
+		delta   int64
+		want    int64  // Tamaño esperado
+		errPart string // Si no está vacío, parte del error esperado
+	}{
+		{"crecer la primaria hasta el final del disco", "p2", 100 * kb, 200 * kb, ""},
+		{"crecer la primaria sobre la siguiente", "p1", kb, 0, "no hay espacio libre suficiente"},
+		{"reducir la primaria", "p1", -40 * kb, 60 * kb, ""},
+		{"reducir la primaria a cero", "p1", -100 * kb, 0, "mayor a cero"},
+		{"crecer la última lógica", "l2", 50 * kb, 150 * kb, ""},
+		{"crecer la lógica sobre la siguiente", "l1", kb, 0, "no hay espacio libre suficiente"},
+		{"reducir la lógica", "l1", -60 * kb, 40 * kb, ""},
+		{"reducir la lógica a cero", "l2", -200 * kb, 0, "mayor a cero"},
+		{"reducir la extendida sin cortar sus lógicas", "ext", -50 * kb, 350 * kb, ""},
+		{"reducir la extendida cortando sus lógicas", "ext", -200 * kb, 0, "sin cortar su contenido"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := newTestDisk(t, "64", testPartition{"p1", "p", 100}, testPartition{"ext", "e", 400}, testPartition{"l1", "l", 100},
+				testPartition{"l2", "l", 100}, testPartition{"p2", "p", 100})
+			before, _ := partitionsOf(t, path)
+
+			_, err := ResizePartition(path, test.target, test.delta)
+			after, _ := partitionsOf(t, path)
+			if test.errPart != "" {
+				if err == nil || !strings.Contains(err.Error(), test.errPart) {
+					t.Fatalf("error %v, se esperaba %q", err, test.errPart)
+				}
+				if after[test.target] != before[test.target] {
+					t.Errorf("la partición cambió aunque -add falló: %+v", after[test.target])
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := after[test.target]; got.Size != test.want || got.Start != before[test.target].Start {
+				t.Errorf("%s: inicio %d, tamaño %d; se esperaba inicio %d, tamaño %d", test.target, got.Start, got.Size, before[test.target].Start, test.want)
+			}
+			for name, partition := range after {
+				if name != test.target && partition != before[name] {
+					t.Errorf("%s cambió al redimensionar %s", name, test.target)
+				}
+			}
+		})
+	}
+}
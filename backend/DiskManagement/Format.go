@@ -0,0 +1,176 @@
+package DiskManagement
+
+import (
+	"backend/Structs"
+	"backend/Utilities"
+	"encoding/binary"
+	"fmt"
+	"os"
+)
+
+// Lectura y escritura del MBR, los EBR y los superbloques según el formato del disco.
+// Todo el código trabaja con Structs.MBR, Structs.EBR y Structs.Superblock (64 bits);
+// en un disco de 32 bits se convierten al leer y al escribir.
+
+// DiskFormat detecta el formato del disco por sus primeros bytes; los discos GPT siempre son de 64 bits
+func DiskFormat(file *os.File) (int32, error) {
+	var header [2]int32
+	if err := Utilities.ReadObject(file, &header, 0); err != nil {
+		return 0, fmt.Errorf("no se pudo leer el encabezado del disco: %v", err)
+	}
+	if header[0] != Structs.FormatTag {
+		if isGPT(file) {
+			return Structs.Format64, nil
+		}
+		return Structs.Format32, nil
+	}
+	if header[1] != Structs.Format64 {
+		return 0, fmt.Errorf("versión de formato %d no soportada", header[1])
+	}
+	return Structs.Format64, nil
+}
+
+// MBRSize, EBRSize y SuperblockSize dan lo que ocupan esas estructuras en un disco del formato indicado
+func MBRSize(format int32) int64 {
+	if format == Structs.Format32 {
+		return int64(binary.Size(Structs.MBR32{}))
+	}
+	return int64(binary.Size(Structs.MBR{}))
+}
+
+func EBRSize(format int32) int64 {
+	if format == Structs.Format32 {
+		return int64(binary.Size(Structs.EBR32{}))
+	}
+	return int64(binary.Size(Structs.EBR{}))
+}
+
+func SuperblockSize(format int32) int64 {
+	if format == Structs.Format32 {
+		return int64(binary.Size(Structs.Superblock32{}))
+	}
+	return int64(binary.Size(Structs.Superblock{}))
+}
+
+// ReadMBR lee el MBR del disco; Version indica su formato. Falla si el disco tiene una GPT.
+func ReadMBR(file *os.File) (Structs.MBR, error) {
+	var mbr Structs.MBR
+	if isGPT(file) {
+		return mbr, fmt.Errorf("el disco tiene una tabla GPT, no un MBR")
+	}
+	format, err := DiskFormat(file)
+	if err != nil {
+		return mbr, err
+	}
+	if format == Structs.Format32 {
+		var mbr32 Structs.MBR32
+		if err := Utilities.ReadObject(file, &mbr32, 0); err != nil {
+			return mbr, err
+		}
+		return mbr32.Expand(), nil
+	}
+	err = Utilities.ReadObject(file, &mbr, 0)
+	return mbr, err
+}
+
+// WriteMBR escribe el MBR en el formato que indica su Version. En 32 bits falla si un valor no cabe.
+func WriteMBR(file *os.File, mbr Structs.MBR) error {
+	if mbr.Version == Structs.Format32 {
+		mbr32, err := Structs.ShrinkMBR(mbr)
+		if err != nil {
+			return err
+		}
+		return Utilities.WriteObject(file, mbr32, 0)
+	}
+	mbr.Tag = Structs.FormatTag
+	mbr.Version = Structs.Format64
+	return Utilities.WriteObject(file, mbr, 0)
+}
+
+// ReadEBR lee el EBR que está en position
+func ReadEBR(file *os.File, position int64) (Structs.EBR, error) {
+	format, err := DiskFormat(file)
+	if err != nil {
+		return Structs.EBR{}, err
+	}
+	return readEBRFormat(file, format, position)
+}
+
+func readEBRFormat(file *os.File, format int32, position int64) (Structs.EBR, error) {
+	var ebr Structs.EBR
+	if format == Structs.Format32 {
+		var ebr32 Structs.EBR32
+		if err := Utilities.ReadObject(file, &ebr32, position); err != nil {
+			return ebr, err
+		}
+		return ebr32.Expand(), nil
+	}
+	err := Utilities.ReadObject(file, &ebr, position)
+	return ebr, err
+}
+
+// WriteEBR escribe el EBR en position, en el formato del disco
+func WriteEBR(file *os.File, ebr Structs.EBR, position int64) error {
+	format, err := DiskFormat(file)
+	if err != nil {
+		return err
+	}
+	if format == Structs.Format32 {
+		ebr32, err := Structs.ShrinkEBR(ebr)
+		if err != nil {
+			return err
+		}
+		return Utilities.WriteObject(file, ebr32, position)
+	}
+	return Utilities.WriteObject(file, ebr, position)
+}
+
+// ReadSuperblock lee el superbloque que empieza en position
+func ReadSuperblock(file *os.File, position int64) (Structs.Superblock, error) {
+	format, err := DiskFormat(file)
+	if err != nil {
+		return Structs.Superblock{}, err
+	}
+	return readSuperblockFormat(file, format, position)
+}
+
+func readSuperblockFormat(file *os.File, format int32, position int64) (Structs.Superblock, error) {
+	var superblock Structs.Superblock
+	if format == Structs.Format32 {
+		var superblock32 Structs.Superblock32
+		if err := Utilities.ReadObject(file, &superblock32, position); err != nil {
+			return superblock, err
+		}
+		return superblock32.Expand(), nil
+	}
+	err := Utilities.ReadObject(file, &superblock, position)
+	return superblock, err
+}
+
+// WriteSuperblock escribe el superbloque en position, en el formato del disco
+func WriteSuperblock(file *os.File, superblock Structs.Superblock, position int64) error {
+	format, err := DiskFormat(file)
+	if err != nil {
+		return err
+	}
+	return writeSuperblockFormat(file, format, superblock, position)
+}
+
+func writeSuperblockFormat(file *os.File, format int32, superblock Structs.Superblock, position int64) error {
+	if format == Structs.Format32 {
+		superblock32, err := Structs.ShrinkSuperblock(superblock)
+		if err != nil {
+			return err
+		}
+		return Utilities.WriteObject(file, superblock32, position)
+	}
+	return Utilities.WriteObject(file, superblock, position)
+}
+
+// formatBits da el nombre del formato para los mensajes: 32 o 64
+func formatBits(format int32) int {
+	if format == Structs.Format32 {
+		return 32
+	}
+	return 64
+}
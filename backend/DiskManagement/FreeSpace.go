@@ -0,0 +1,140 @@
+package DiskManagement
+
+import (
+	"backend/Structs"
+	"fmt"
+	"sort"
+)
+
+// Gap es un rango contiguo del disco: desde Start, Size bytes
+type Gap struct {
+	Start int64
+	Size  int64
+}
+
+// FreeGaps devuelve los espacios libres entre start y end que no ocupa ninguno de los rangos usados.
+// Los rangos usados pueden venir en cualquier orden.
+func FreeGaps(start int64, end int64, used []Gap) []Gap {
+	sorted := append([]Gap(nil), used...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	var gaps []Gap
+	pos := start
+	for _, block := range sorted {
+		if block.Size <= 0 {
+			continue
+		}
+		if block.Start > pos {
+			gaps = append(gaps, Gap{Start: pos, Size: block.Start - pos})
+		}
+		if block.Start+block.Size > pos {
+			pos = block.Start + block.Size
+		}
+	}
+	if end > pos {
+		gaps = append(gaps, Gap{Start: pos, Size: end - pos})
+	}
+	return gaps
+}
+
+// ChooseGap elige el espacio libre donde se coloca una partición de size bytes según el ajuste:
+// 'b' mejor ajuste (el más pequeño donde cabe), 'w' peor ajuste (el más grande) y 'f' primer ajuste.
+// Un ajuste desconocido se trata como primer ajuste.
+func ChooseGap(gaps []Gap, size int64, fit byte) (Gap, bool) {
+	chosen := -1
+	for i, gap := range gaps {
+		if gap.Size < size {
+			continue
+		}
+		switch {
+		case chosen == -1:
+			chosen = i
+		case fit == 'b' && gap.Size < gaps[chosen].Size:
+			chosen = i
+		case fit == 'w' && gap.Size > gaps[chosen].Size:
+			chosen = i
+		}
+		if fit != 'b' && fit != 'w' {
+			break
+		}
+	}
+	if chosen == -1 {
+		return Gap{}, false
+	}
+	return gaps[chosen], true
+}
+
+// LargestGap devuelve el tamaño del espacio libre más grande, o 0 si no hay ninguno
+func LargestGap(gaps []Gap) int64 {
+	var largest int64
+	for _, gap := range gaps {
+		if gap.Size > largest {
+			largest = gap.Size
+		}
+	}
+	return largest
+}
+
+// PlacePartition elige según el ajuste del disco el espacio libre donde empieza una partición
+// primaria o extendida de size bytes
+func PlacePartition(gaps []Gap, size int64, fit byte, name string) (Gap, error) {
+	chosen, ok := ChooseGap(gaps, size, fit)
+	if !ok {
+		return Gap{}, fmt.Errorf("no hay un espacio libre de %d bytes para la partición %s; el espacio libre más grande es de %d bytes", size, name, LargestGap(gaps))
+	}
+	return chosen, nil
+}
+
+// PlaceLogical elige según el ajuste de la extendida el espacio donde va una partición lógica de
+// size bytes: su EBR ocupa el inicio del espacio elegido y sus datos empiezan justo después
+func PlaceLogical(gaps []Gap, ebrSize int64, size int64, fit byte, name string) (Gap, error) {
+	chosen, ok := ChooseGap(gaps, ebrSize+size, fit)
+	if !ok {
+		return Gap{}, fmt.Errorf("la partición lógica %s no cabe en la partición extendida: necesita %d bytes y el espacio libre más grande es de %d bytes", name, size, max(LargestGap(gaps)-ebrSize, 0))
+	}
+	return chosen, nil
+}
+
+// LogicalNumber devuelve el número de la lógica que está en la posición index (desde 0) de la cadena
+// de EBR, que va en orden de posición dentro de la extendida: 5, 6, ... después de los slots del MBR
+func LogicalNumber(index int) int {
+	return len(Structs.MBR{}.Partitions) + 1 + index
+}
+
+// CheckResize valida el nuevo tamaño de la partición name, que empieza en start, al sumarle delta
+// bytes. minEnd es el final de lo que no se puede cortar y gaps son los espacios libres: para crecer
+// solo se usa el que empieza justo donde termina la partición.
+func CheckResize(name string, start int64, size int64, delta int64, minEnd int64, gaps []Gap) (int64, error) {
+	newSize := size + delta
+	if delta > 0 {
+		var available int64
+		for _, gap := range gaps {
+			if gap.Start == start+size {
+				available = gap.Size
+			}
+		}
+		if delta > available {
+			return 0, fmt.Errorf("no hay espacio libre suficiente después de la partición %s: se necesitan %d bytes y hay %d bytes libres", name, delta, available)
+		}
+		return newSize, nil
+	}
+	if newSize <= 0 {
+		return 0, fmt.Errorf("la partición %s quedaría con %d bytes; su tamaño debe ser mayor a cero", name, newSize)
+	}
+	if start+newSize < minEnd {
+		return 0, fmt.Errorf("la partición %s no puede reducirse a menos de %d bytes sin cortar su contenido", name, minEnd-start)
+	}
+	return newSize, nil
+}
+
+// FitName devuelve el nombre del ajuste para los mensajes
+func FitName(fit byte) string {
+	switch fit {
+	case 'b':
+		return "mejor ajuste"
+	case 'w':
+		return "peor ajuste"
+	default:
+		return "primer ajuste"
+	}
+}
@@ -0,0 +1,161 @@
+package DiskManagement
+
+import (
+	"backend/Structs"
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Posición de HeaderCRC dentro del encabezado: después de Tag, Version y Signature
+const gptHeaderCRCOffset = 4 + 4 + 8
+
+// newGPTDisk crea un disco GPT de 1 MB con 8 entradas y dos particiones
+func newGPTDisk(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "gpt.mia")
+	if _, err := Mkdisk(1, "ff", "m", path, "none", "64", "gpt", 8); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"p1", "p2"} {
+		if _, err := Fdisk(100, path, name, "k", "p", "w"); err != nil {
+			t.Fatalf("fdisk %s: %v", name, err)
+		}
+	}
+	return path
+}
+
+// readRaw lee size bytes del archivo desde position
+func readRaw(t *testing.T, file *os.File, position int64, size int64) []byte {
+	t.Helper()
+	data := make([]byte, size)
+	if _, err := file.ReadAt(data, position); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// checkGPTHeader revisa el CRC32 del encabezado en position y el de su arreglo de entradas,
+// calculados sobre los bytes del disco
+func checkGPTHeader(t *testing.T, file *os.File, position int64) Structs.GPTHeader {
+	t.Helper()
+	raw := readRaw(t, file, position, GPTHeaderSize())
+	var header Structs.GPTHeader
+	if err := binary.Read(bytes.NewReader(raw), binary.LittleEndian, &header); err != nil {
+		t.Fatal(err)
+	}
+	if header.Signature != Structs.GPTSignature || header.MyPosition != position {
+		t.Fatalf("encabezado en %d: firma %q, posición %d", position, header.Signature, header.MyPosition)
+	}
+
+	copy(raw[gptHeaderCRCOffset:], []byte{0, 0, 0, 0})
+	if want := crc32.ChecksumIEEE(raw); header.HeaderCRC != want {
+		t.Errorf("encabezado en %d: HeaderCRC %08x, se esperaba %08x", position, header.HeaderCRC, want)
+	}
+	entries := readRaw(t, file, header.EntriesStart, int64(header.EntryCount)*int64(header.EntrySize))
+	if want := crc32.ChecksumIEEE(entries); header.EntriesCRC != want {
+		t.Errorf("encabezado en %d: EntriesCRC %08x, se esperaba %08x", position, header.EntriesCRC, want)
+	}
+	return header
+}
+
+func TestGPTHeadersAndChecksums(t *testing.T) {
+	path := newGPTDisk(t)
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	primary := checkGPTHeader(t, file, 0)
+	backup := checkGPTHeader(t, file, info.Size()-GPTHeaderSize())
+
+	// El respaldo apunta al principal y tiene su arreglo justo después del último byte usable
+	if primary.AltPosition != backup.MyPosition || backup.AltPosition != primary.MyPosition {
+		t.Errorf("posiciones cruzadas: principal %d->%d, respaldo %d->%d", primary.MyPosition, primary.AltPosition, backup.MyPosition, backup.AltPosition)
+	}
+	if primary.EntriesStart != GPTHeaderSize() || backup.EntriesStart != primary.LastUsable+1 {
+		t.Errorf("arreglos en %d y %d; se esperaban %d y %d", primary.EntriesStart, backup.EntriesStart, GPTHeaderSize(), primary.LastUsable+1)
+	}
+	if backup.EntriesStart+int64(backup.EntryCount)*GPTEntrySize() != backup.MyPosition {
+		t.Errorf("el arreglo de respaldo no termina donde empieza su encabezado")
+	}
+	if primary.EntriesCRC != backup.EntriesCRC || primary.DiskGUID != backup.DiskGUID {
+		t.Error("el respaldo no tiene las mismas entradas o el mismo GUID que el principal")
+	}
+	if primary.FirstUsable != GPTHeaderSize()+int64(primary.EntryCount)*GPTEntrySize() {
+		t.Errorf("FirstUsable = %d, no está después del arreglo principal", primary.FirstUsable)
+	}
+}
+
+func TestGPTRecovery(t *testing.T) {
+	tests := []struct {
+		name      string
+		damage    func(header Structs.GPTHeader) []int64 // Posiciones donde se escribe basura
+		recovered bool
+		fails     bool
+	}{
+		{"sin daños", func(Structs.GPTHeader) []int64 { return nil }, false, false},
+		{"encabezado principal", func(Structs.GPTHeader) []int64 { return []int64{40} }, true, false},
+		{"entradas principales", func(h Structs.GPTHeader) []int64 { return []int64{h.EntriesStart + 40} }, true, false},
+		{"encabezado de respaldo", func(h Structs.GPTHeader) []int64 { return []int64{h.AltPosition + 40} }, false, false},
+		{"ambos encabezados", func(h Structs.GPTHeader) []int64 { return []int64{40, h.AltPosition + 40} }, false, true},
+		{"principal y entradas de respaldo", func(h Structs.GPTHeader) []int64 { return []int64{40, h.LastUsable + 41} }, false, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := newGPTDisk(t)
+			file, err := os.OpenFile(path, os.O_RDWR, 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			original, err := ReadTable(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			info, _ := file.Stat()
+			for _, position := range test.damage(*original.GPT) {
+				if _, err := file.WriteAt([]byte{0xDE, 0xAD, 0xBE, 0xEF}, position); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			table, err := ReadTable(file)
+			if test.fails {
+				if err == nil {
+					t.Fatal("se esperaba un error con las dos GPT dañadas")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if table.Recovered != test.recovered {
+				t.Errorf("Recovered = %v, se esperaba %v", table.Recovered, test.recovered)
+			}
+			for i := range original.Partitions {
+				if table.Partitions[i] != original.Partitions[i] {
+					t.Errorf("entrada %d: %+v, se esperaba %+v", i, table.Partitions[i], original.Partitions[i])
+				}
+			}
+
+			// Escribir la tabla repara la GPT dañada
+			if err := WriteTable(file, table); err != nil {
+				t.Fatal(err)
+			}
+			checkGPTHeader(t, file, 0)
+			checkGPTHeader(t, file, info.Size()-GPTHeaderSize())
+			if repaired, err := ReadTable(file); err != nil || repaired.Recovered {
+				t.Errorf("después de WriteTable: Recovered = %v, %v", repaired != nil && repaired.Recovered, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package DiskManagement
+
+import (
+	"backend/Structs"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MountInfo describe una partición montada para los comandos mounted y el frontend
+type MountInfo struct {
+	ID         string `json:"id"`
+	Path       string `json:"path"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`                 // "p" o "l"
+	Start      int64  `json:"start"`                // Byte donde empieza la partición
+	Size       int64  `json:"size"`                 // Tamaño en bytes
+	Filesystem string `json:"filesystem,omitempty"` // "ext2" o vacío si no tiene formato
+	LoggedIn   bool   `json:"loggedIn"`
+	Unclean    bool   `json:"unclean,omitempty"`
+	Error      string `json:"error,omitempty"` // Si no se pudo leer la partición en su disco
+}
+
+// PartitionInfo es una entrada de la tabla de particiones de un disco
+type PartitionInfo struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"` // "p", "e" o "l"
+	Fit    string `json:"fit"`
+	Status string `json:"status"` // "1" si está montada
+	ID     string `json:"id,omitempty"`
+	Start  int64  `json:"start"`
+	Size   int64  `json:"size"`
+}
+
+// DiskInfo describe un archivo .mia encontrado por lsdisk
+type DiskInfo struct {
+	Path         string          `json:"path"`
+	Size         int64           `json:"size"`
+	Format       int             `json:"format"` // 32 o 64 bits
+	Table        string          `json:"table"`  // "mbr" o "gpt"
+	Signature    int32           `json:"signature"`
+	CreationDate string          `json:"creationDate"`
+	Fit          string          `json:"fit"`
+	Partitions   []PartitionInfo `json:"partitions"`
+	Error        string          `json:"error,omitempty"`   // Si el archivo no tiene un MBR legible
+	Warning      string          `json:"warning,omitempty"` // Si la GPT principal está dañada y se leyó la de respaldo
+}
+
+// ListMounted devuelve las particiones montadas ordenadas por ID, con lo que se lee de cada disco
+func ListMounted() []MountInfo {
+	list := []MountInfo{}
+	for _, partitions := range mountedPartitions {
+		for _, mounted := range partitions {
+			info := MountInfo{ID: mounted.ID, Path: mounted.Path, Name: mounted.Name, LoggedIn: mounted.LoggedIn, Unclean: mounted.Unclean}
+			if err := fillMountInfo(&info); err != nil {
+				info.Error = err.Error()
+			}
+			list = append(list, info)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// fillMountInfo completa el tipo, la ubicación y el sistema de archivos de una partición montada
+func fillMountInfo(info *MountInfo) error {
+	file, err := os.Open(info.Path)
+	if err != nil {
+		return fmt.Errorf("no se pudo abrir el disco: %v", err)
+	}
+	defer file.Close()
+
+	partition, err := LocatePartition(file, info.ID)
+	if err != nil {
+		return err
+	}
+	info.Type = string(partition.Type[0])
+	info.Start = partition.Start
+	info.Size = partition.Size
+	if FilesystemEnd(file, partition.Start) > 0 {
+		info.Filesystem = "ext2"
+	}
+	return nil
+}
+
+// ListDisks busca archivos .mia en dir (y sus subcarpetas) y lee el MBR y las EBR de cada uno
+func ListDisks(dir string) ([]DiskInfo, error) {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("la carpeta %s no existe", dir)
+	}
+
+	disks := []DiskInfo{}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(path), ".mia") {
+			return nil
+		}
+		disk := DiskInfo{Path: path}
+		if err := readDiskInfo(&disk); err != nil {
+			disk.Error = err.Error()
+		}
+		disks = append(disks, disk)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo recorrer la carpeta %s: %v", dir, err)
+	}
+	return disks, nil
+}
+
+// readDiskInfo lee la tabla de particiones del disco y arma su tabla de particiones, con las lógicas después de su extendida
+func readDiskInfo(disk *DiskInfo) error {
+	file, err := os.Open(disk.Path)
+	if err != nil {
+		return fmt.Errorf("no se pudo abrir el disco: %v", err)
+	}
+	defer file.Close()
+
+	table, err := ReadTable(file)
+	if err != nil {
+		return fmt.Errorf("no se pudo leer la tabla de particiones: %v", err)
+	}
+	mbr := table.MBR
+	disk.Size = mbr.MbrSize
+	disk.Format = formatBits(mbr.Version)
+	disk.Table = table.Kind()
+	if table.Recovered {
+		disk.Warning = "la GPT principal está dañada; se leyó la de respaldo"
+	}
+	disk.Signature = mbr.Signature
+	disk.CreationDate = strings.TrimRight(string(mbr.CreationDate[:]), "\x00")
+	disk.Fit = strings.TrimRight(string(mbr.Fit[:]), "\x00")
+	disk.Partitions = []PartitionInfo{}
+
+	partitions := make([]Structs.Partition, 0, 4)
+	for _, partition := range table.Partitions {
+		if partition.Size > 0 {
+			partitions = append(partitions, partition)
+		}
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].Start < partitions[j].Start })
+
+	for _, partition := range partitions {
+		disk.Partitions = append(disk.Partitions, partitionInfo(partition))
+		if partition.Type[0] != 'e' {
+			continue
+		}
+		chain, err := readEBRChain(file, partition)
+		for _, entry := range chain {
+			if entry.EBR.PartSize > 0 {
+				info := partitionInfo(logicalView(entry.EBR))
+				if info.ID == "" {
+					info.ID = mountedID(disk.Path, info.Name)
+				}
+				disk.Partitions = append(disk.Partitions, info)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// partitionInfo convierte una partición (o la vista de una lógica) a su entrada en la tabla
+func partitionInfo(partition Structs.Partition) PartitionInfo {
+	trim := func(b []byte) string { return strings.TrimRight(string(b), "\x00") }
+	return PartitionInfo{
+		Name:   trim(partition.Name[:]),
+		Type:   trim(partition.Type[:]),
+		Fit:    trim(partition.Fit[:]),
+		Status: trim(partition.Status[:]),
+		ID:     trim(partition.Id[:]),
+		Start:  partition.Start,
+		Size:   partition.Size,
+	}
+}
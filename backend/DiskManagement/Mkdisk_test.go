@@ -0,0 +1,113 @@
+package DiskManagement
+
+import (
+	"backend/Structs"
+	"backend/Utilities"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestMkdiskZero revisa que con -zero=full y -zero=none el disco tenga exactamente el tamaño pedido y
+// que después del MBR todo sean ceros, también cuando en la ruta ya había un archivo con datos
+func TestMkdiskZero(t *testing.T) {
+	const size = 64 * 1024
+	tests := []struct {
+		name     string
+		previous int // Bytes que ya tenía el archivo; -1 si no existía
+	}{
+		{"disco nuevo", -1},
+		{"sobre un archivo más grande", 3 * size},
+		{"sobre un archivo más pequeño", size / 2},
+		{"sobre un archivo del mismo tamaño", size},
+	}
+	for _, zero := range []string{"full", "none"} {
+		for _, test := range tests {
+			t.Run(zero+"/"+test.name, func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "disco.mia")
+				if test.previous >= 0 {
+					if err := os.WriteFile(path, bytes.Repeat([]byte{0xA5}, test.previous), 0644); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if _, err := Mkdisk(size/1024, "ff", "k", path, zero, "32", "mbr", 0); err != nil {
+					t.Fatal(err)
+				}
+
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(data) != size {
+					t.Fatalf("el disco tiene %d bytes, se esperaban %d", len(data), size)
+				}
+				if i := bytes.IndexFunc(data[MBRSize(Structs.Format32):], func(r rune) bool { return r != 0 }); i >= 0 {
+					t.Errorf("byte distinto de cero en la posición %d", MBRSize(Structs.Format32)+int64(i))
+				}
+			})
+		}
+	}
+}
+
+// Tamaños de disco medidos, en megabytes
+var benchmarkSizes = []int{1, 50, 500}
+
+// mkdiskPerByte es el llenado anterior de mkdisk (una escritura por byte), como referencia
+func mkdiskPerByte(path string, size int) error {
+	if err := Utilities.CreateFile(path); err != nil {
+		return err
+	}
+	file, err := Utilities.OpenFile(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	for i := 0; i < size; i++ {
+		if err := Utilities.WriteObject(file, byte(0), int64(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// benchmarkMkdisk crea en cada iteración un disco de cada tamaño con create y lo borra
+func benchmarkMkdisk(b *testing.B, maxSize int, create func(path string, size int) error) {
+	for _, megabytes := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%dMB", megabytes), func(b *testing.B) {
+			if megabytes > maxSize {
+				b.Skipf("demasiado lento para %d MB", megabytes)
+			}
+			path := filepath.Join(b.TempDir(), "disco.mia")
+			b.SetBytes(int64(megabytes) * 1024 * 1024)
+			for i := 0; i < b.N; i++ {
+				if err := create(path, megabytes); err != nil {
+					b.Fatal(err)
+				}
+				os.Remove(path)
+			}
+		})
+	}
+}
+
+func BenchmarkMkdiskPerByte(b *testing.B) {
+	// Con 500 MB una escritura por byte tarda varios minutos
+	benchmarkMkdisk(b, 50, func(path string, size int) error {
+		return mkdiskPerByte(path, size*1024*1024)
+	})
+}
+
+func BenchmarkMkdiskFull(b *testing.B) {
+	benchmarkMkdisk(b, 500, func(path string, size int) error {
+		_, err := Mkdisk(size, "ff", "m", path, "full", "32", "mbr", 0)
+		return err
+	})
+}
+
+func BenchmarkMkdiskNone(b *testing.B) {
+	benchmarkMkdisk(b, 500, func(path string, size int) error {
+		_, err := Mkdisk(size, "ff", "m", path, "none", "32", "mbr", 0)
+		return err
+	})
+}
@@ -0,0 +1,210 @@
+package DiskManagement
+
+import (
+	"backend/Structs"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Variables de entorno con las que se configura el esquema de los IDs de montaje
+const (
+	IDPrefixEnv    = "MIA_ID_PREFIX"
+	IDNumberingEnv = "MIA_ID_NUMBERING"
+)
+
+// Formas de calcular el número del ID
+const (
+	NumberingSlot        = "slot"        // Número de la partición en el disco: slot del MBR + 1, o 5, 6, ... para las lógicas
+	NumberingCorrelative = "correlative" // Orden en que cada partición del disco se montó por primera vez
+)
+
+// IDScheme describe cómo se arma un ID de montaje: prefijo + número + letra del disco.
+//
+// El ID se guarda en el campo Id de la partición, que tiene 4 bytes en los discos de 32 bits y 8 en
+// los de 64 bits y GPT (ver IDLength). Las lógicas lo guardan en su EBR en los discos de 64 bits; en
+// los de 32 bits el EBR no tiene ese campo y el ID solo está en la tabla de montajes. Con el
+// prefijo de dos dígitos, en un disco de 32 bits caben los números 1 a 9: basta para los 4 slots,
+// pero con numeración correlativa el disco admite solo 9 particiones distintas. Con un prefijo de un
+// dígito llegan a 99; para más hay que convertir el disco con convertdisk.
+type IDScheme struct {
+	Prefix    string // Dígitos al inicio del ID
+	Numbering string // NumberingSlot o NumberingCorrelative
+}
+
+// DefaultIDScheme usa los dos últimos dígitos del carné y el número de la partición en el disco
+var DefaultIDScheme = IDScheme{Prefix: "09", Numbering: NumberingSlot}
+
+// CurrentIDScheme devuelve el esquema configurado con MIA_ID_PREFIX y MIA_ID_NUMBERING. Falla si los
+// IDs de las 4 primarias de un disco de 32 bits no caben en su campo Id.
+func CurrentIDScheme() (IDScheme, error) {
+	scheme := DefaultIDScheme
+	if prefix := os.Getenv(IDPrefixEnv); prefix != "" {
+		if strings.Trim(prefix, "0123456789") != "" {
+			return scheme, fmt.Errorf("%s inválido '%s': se esperan dígitos", IDPrefixEnv, prefix)
+		}
+		scheme.Prefix = prefix
+	}
+	if numbering := strings.ToLower(os.Getenv(IDNumberingEnv)); numbering != "" {
+		if numbering != NumberingSlot && numbering != NumberingCorrelative {
+			return scheme, fmt.Errorf("%s inválido '%s': se espera %s o %s", IDNumberingEnv, numbering, NumberingSlot, NumberingCorrelative)
+		}
+		scheme.Numbering = numbering
+	}
+	if err := scheme.CheckNumber(len(Structs.MBR32{}.Partitions), Structs.Format32); err != nil {
+		return scheme, fmt.Errorf("%s inválido '%s': %v; use uno o dos dígitos", IDPrefixEnv, scheme.Prefix, err)
+	}
+	return scheme, nil
+}
+
+// IDLength devuelve los bytes del campo Id de una partición en un disco del formato indicado: 4 en
+// el de 32 bits y 8 en el de 64 bits, que es el de los discos GPT
+func IDLength(format int32) int {
+	if format == Structs.Format32 {
+		return len(Structs.Partition32{}.Id)
+	}
+	return len(Structs.Partition{}.Id)
+}
+
+// MountIDLength devuelve el largo máximo del ID al montar una partición primaria o lógica. El EBR de
+// 64 bits tiene un PartId del mismo largo que el Id; el de 32 bits no guarda el ID, así que no hay límite (0).
+func MountIDLength(format int32, logical bool) int {
+	if logical && format == Structs.Format32 {
+		return 0
+	}
+	if logical {
+		return len(Structs.EBR{}.PartId)
+	}
+	return IDLength(format)
+}
+
+// CheckNumber revisa que el ID de la partición con ese número quepa en un disco del formato indicado
+func (s IDScheme) CheckNumber(number int, format int32) error {
+	if id := fmt.Sprintf("%s%da", s.Prefix, number); len(id) > IDLength(format) {
+		return fmt.Errorf("el ID de la partición %d (%s) no cabe en los %d bytes del ID de un disco de %d bits", number, id, IDLength(format), formatBits(format))
+	}
+	return nil
+}
+
+// CheckEntryIDs revisa que con el esquema configurado quepan los IDs de todas las entradas de una
+// GPT de entries entradas; la última es la de número más largo
+func CheckEntryIDs(entries int) error {
+	scheme, err := CurrentIDScheme()
+	if err != nil {
+		return err
+	}
+	if err := scheme.CheckNumber(entries, Structs.Format64); err != nil {
+		return fmt.Errorf("con %d entradas no se podrían montar todas las particiones: %v", entries, err)
+	}
+	return nil
+}
+
+// DiskIDs es lo asignado a un disco para sus IDs: su letra y el correlativo de cada partición
+type DiskIDs struct {
+	Letter      string         `json:"letter"`
+	Correlative map[string]int `json:"correlative,omitempty"`
+}
+
+// IDAssignments guarda por ruta de disco lo que se le asignó. No se borra al desmontar,
+// así un disco conserva su letra y sus particiones su número entre montajes y reinicios.
+type IDAssignments map[string]*DiskIDs
+
+// Asignaciones de los discos montados alguna vez; se guardan junto con la tabla de montajes
+var idAssignments = make(IDAssignments)
+
+// SnapshotIDAssignments devuelve una copia de las asignaciones para poder restaurarlas o simular sobre ellas
+func SnapshotIDAssignments() IDAssignments {
+	snapshot := make(IDAssignments, len(idAssignments))
+	for diskID, disk := range idAssignments {
+		copied := &DiskIDs{Letter: disk.Letter}
+		if disk.Correlative != nil {
+			copied.Correlative = make(map[string]int, len(disk.Correlative))
+			for name, number := range disk.Correlative {
+				copied.Correlative[name] = number
+			}
+		}
+		snapshot[diskID] = copied
+	}
+	return snapshot
+}
+
+// RestoreIDAssignments reemplaza las asignaciones por una copia tomada con SnapshotIDAssignments
+func RestoreIDAssignments(snapshot IDAssignments) {
+	idAssignments = snapshot
+	saveMounts()
+}
+
+// NextMountID calcula el ID de la partición name del disco path; number es su número en el disco
+// (slot + 1 o 5, 6, ... para las lógicas). La letra del disco y el correlativo se toman de ids y,
+// si aún no tienen, se asignan ahí: un disco nuevo recibe la letra siguiente a la mayor asignada.
+// El ID no puede repetir uno de mounts; un correlativo nuevo salta los números ya en uso. length es
+// cuántos bytes tiene el campo donde se guardará el ID (ver IDLength), o 0 si no se guarda en el disco.
+func NextMountID(ids IDAssignments, mounts map[string][]MountedPartition, path string, name string, number int, length int) (string, error) {
+	scheme, err := CurrentIDScheme()
+	if err != nil {
+		return "", err
+	}
+
+	diskID := generateDiskID(path)
+	disk := ids[diskID]
+	if disk == nil {
+		var letter byte = 'a'
+		for _, other := range ids {
+			if other.Letter != "" && other.Letter[0] >= letter {
+				letter = other.Letter[0] + 1
+			}
+		}
+		if letter > 'z' {
+			return "", fmt.Errorf("no quedan letras para identificar el disco %s", path)
+		}
+		disk = &DiskIDs{Letter: string(letter)}
+		ids[diskID] = disk
+	}
+
+	if scheme.Numbering == NumberingCorrelative {
+		if disk.Correlative == nil {
+			disk.Correlative = make(map[string]int)
+		}
+		correlative, ok := disk.Correlative[name]
+		if !ok {
+			for _, used := range disk.Correlative {
+				correlative = max(correlative, used)
+			}
+			correlative++
+			for idInUse(mounts, fmt.Sprintf("%s%d%s", scheme.Prefix, correlative, disk.Letter)) != nil {
+				correlative++
+			}
+			disk.Correlative[name] = correlative
+		}
+		number = correlative
+	}
+
+	id := fmt.Sprintf("%s%d%s", scheme.Prefix, number, disk.Letter)
+	if length > 0 && len(id) > length {
+		if scheme.Numbering == NumberingCorrelative {
+			return "", fmt.Errorf("el ID %s no cabe en los %d bytes del ID de la partición: el disco ya usó todos los correlativos que caben con el prefijo %s (use un prefijo más corto o convertdisk)", id, length, scheme.Prefix)
+		}
+		return "", fmt.Errorf("el ID %s no cabe en los %d bytes del ID de la partición", id, length)
+	}
+	if other := idInUse(mounts, id); other != nil {
+		return "", fmt.Errorf("el ID %s ya lo usa la partición %s de %s (¿cambió el esquema de IDs?)", id, other.Name, other.Path)
+	}
+	return id, nil
+}
+
+// idInUse devuelve la partición de mounts que ya tiene el ID, o nil si está libre
+func idInUse(mounts map[string][]MountedPartition, id string) *MountedPartition {
+	for _, partitions := range mounts {
+		for i := range partitions {
+			if SameID(partitions[i].ID, id) {
+				return &partitions[i]
+			}
+		}
+	}
+	return nil
+}
+
+// SameID compara dos IDs de montaje sin importar mayúsculas y minúsculas (091a y 091A son el mismo)
+func SameID(a string, b string) bool {
+	return strings.EqualFold(a, b)
+}
@@ -0,0 +1,140 @@
+package DiskManagement
+
+import (
+	"backend/Structs"
+	"backend/Utilities"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Nombre del archivo de la tabla de montajes dentro de la carpeta de datos
+const mountsFile = "mounts.json"
+
+// savedMount es una entrada de la tabla de montajes tal como se guarda en disco.
+// La sesión no se guarda: después de reiniciar hay que volver a hacer login.
+type savedMount struct {
+	Path    string `json:"path"`
+	Name    string `json:"name"`
+	ID      string `json:"id"`
+	Unclean bool   `json:"unclean,omitempty"`
+}
+
+// mountsState es el contenido del archivo: las letras y correlativos asignados a cada disco y los montajes actuales
+type mountsState struct {
+	Disks  IDAssignments `json:"disks"`
+	Mounts []savedMount  `json:"mounts"`
+}
+
+// mountsPath devuelve la ruta del archivo de la tabla de montajes
+func mountsPath() string {
+	return filepath.Join(Utilities.DataDir(), mountsFile)
+}
+
+// saveMounts guarda la tabla de montajes y las asignaciones de IDs; se llama cada vez que cambian.
+// Un error al guardar no hace fallar el comando, solo se informa.
+func saveMounts() {
+	var saved []savedMount
+	for _, partitions := range mountedPartitions {
+		for _, partition := range partitions {
+			saved = append(saved, savedMount{Path: partition.Path, Name: partition.Name, ID: partition.ID, Unclean: partition.Unclean})
+		}
+	}
+	sort.Slice(saved, func(i, j int) bool { return saved[i].ID < saved[j].ID })
+
+	if err := writeMounts(mountsState{Disks: idAssignments, Mounts: saved}); err != nil {
+		fmt.Println("Error al guardar la tabla de montajes:", err)
+	}
+}
+
+// writeMounts escribe el archivo completo y lo reemplaza de una vez para no dejarlo a medias
+func writeMounts(state mountsState) error {
+	if err := os.MkdirAll(Utilities.DataDir(), os.ModePerm); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	temp := mountsPath() + ".tmp"
+	if err := os.WriteFile(temp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(temp, mountsPath())
+}
+
+// LoadMounts restaura la tabla de montajes guardada y la concilia con los discos: se descartan
+// las entradas cuyo disco ya no existe o cuyo MBR no dice que la partición sigue montada con ese ID.
+// Devuelve una línea por cada entrada restaurada o descartada.
+func LoadMounts() (string, error) {
+	data, err := os.ReadFile(mountsPath())
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("no se pudo leer la tabla de montajes: %v", err)
+	}
+	var state mountsState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return "", fmt.Errorf("tabla de montajes inválida en %s: %v", mountsPath(), err)
+	}
+	if state.Disks == nil {
+		state.Disks = make(IDAssignments)
+	}
+
+	var logs string
+	restored := make(map[string][]MountedPartition)
+	for _, entry := range state.Mounts {
+		if reason := reconcileMount(entry); reason != "" {
+			logs += fmt.Sprintf("Montaje %s descartado (%s en %s): %s\n", entry.ID, entry.Name, entry.Path, reason)
+			continue
+		}
+		diskID := generateDiskID(entry.Path)
+		restored[diskID] = append(restored[diskID], MountedPartition{Path: entry.Path, Name: entry.Name, ID: entry.ID, Status: '1', Unclean: entry.Unclean})
+		logs += fmt.Sprintf("Montaje %s restaurado (%s en %s)\n", entry.ID, entry.Name, entry.Path)
+	}
+
+	mountedPartitions = restored
+	idAssignments = state.Disks
+	saveMounts()
+	return logs, nil
+}
+
+// reconcileMount revisa una entrada guardada contra el MBR de su disco. Si la entrada ya no vale
+// devuelve el motivo; si la partición sigue marcada como montada en el MBR, se libera para poder volver a montarla.
+func reconcileMount(entry savedMount) string {
+	file, err := os.OpenFile(entry.Path, os.O_RDWR, 0644)
+	if err != nil {
+		return "el disco ya no existe"
+	}
+	defer file.Close()
+
+	table, err := ReadTable(file)
+	if err != nil {
+		return "no se pudo leer la tabla de particiones"
+	}
+
+	ref, err := findPartition(file, table, byName(entry.Name))
+	if err != nil {
+		return "no se pudo leer la partición extendida"
+	}
+	if ref == nil {
+		return "la partición ya no existe en el disco"
+	}
+	// Las lógicas de 32 bits solo guardan el estado en su EBR; en las demás se compara el ID del disco
+	id := strings.TrimRight(string(ref.Partition.Id[:]), "\x00")
+	if ref.Slot < 0 && table.MBR.Version == Structs.Format32 {
+		id = entry.ID
+	}
+	if ref.Partition.Status[0] == '1' && id == entry.ID {
+		return ""
+	}
+	if ref.Partition.Status[0] == '1' {
+		ref.setMountState(file, table, '0', "")
+		return fmt.Sprintf("el disco la tiene montada con otro ID (%s); se liberó", id)
+	}
+	return "el disco ya no la tiene montada"
+}
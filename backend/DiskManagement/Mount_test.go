@@ -0,0 +1,150 @@
+package DiskManagement
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// resetMounts deja vacías la tabla de montajes y las asignaciones de IDs, y guarda mounts.json en una
+// carpeta temporal; al terminar el test se restauran las de antes
+func resetMounts(t *testing.T) {
+	t.Helper()
+	t.Setenv("MIA_DATA_DIR", t.TempDir())
+	mounts, ids := mountedPartitions, idAssignments
+	mountedPartitions, idAssignments = make(map[string][]MountedPartition), make(IDAssignments)
+	t.Cleanup(func() { mountedPartitions, idAssignments = mounts, ids })
+}
+
+// testPartition es una partición que se crea con Fdisk en los tests, con su tamaño en KB
+type testPartition struct {
+	name  string
+	type_ string
+	size  int
+}
+
+// newTestDisk crea un disco de 1 MB con el formato indicado y las particiones dadas, en orden
+func newTestDisk(t *testing.T, format string, partitions ...testPartition) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "disco.mia")
+	if _, err := Mkdisk(1, "ff", "m", path, "none", format, "mbr", 0); err != nil {
+		t.Fatal(err)
+	}
+	for _, partition := range partitions {
+		if _, err := Fdisk(partition.size, path, partition.name, "k", partition.type_, "w"); err != nil {
+			t.Fatalf("fdisk %s: %v", partition.name, err)
+		}
+	}
+	return path
+}
+
+// logicalEBR devuelve el EBR de la partición lógica name
+func logicalEBR(t *testing.T, path string, name string) ebrEntry {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	table, err := ReadTable(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := findPartition(file, table, byName(name))
+	if err != nil || ref == nil || ref.Slot >= 0 {
+		t.Fatalf("no se encontró la lógica %s: %v", name, err)
+	}
+	return ref.EBR
+}
+
+func TestMountLogicalID(t *testing.T) {
+	tests := []struct {
+		format string
+		stored bool // Si el EBR guarda el ID
+	}{
+		{"64", true},
+		{"32", false},
+	}
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			resetMounts(t)
+			path := newTestDisk(t, test.format, testPartition{"ext", "e", 600}, testPartition{"l1", "l", 200}, testPartition{"l2", "l", 200})
+			if _, err := Mount(path, "l2"); err != nil {
+				t.Fatal(err)
+			}
+			id := mountedID(path, "l2")
+
+			ebr := logicalEBR(t, path, "l2").EBR
+			if ebr.PartMount != '1' {
+				t.Errorf("PartMount = %c, se esperaba 1", ebr.PartMount)
+			}
+			want := ""
+			if test.stored {
+				want = id
+			}
+			if got := strings.TrimRight(string(ebr.PartId[:]), "\x00"); got != want {
+				t.Errorf("PartId = %q, se esperaba %q", got, want)
+			}
+
+			// La partición se encuentra por su ID y el montaje sobrevive a un reinicio
+			file, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			partition, err := LocatePartition(file, id)
+			file.Close()
+			if err != nil || strings.TrimRight(string(partition.Name[:]), "\x00") != "l2" {
+				t.Fatalf("LocatePartition(%s) = %s, %v", id, partition.Name, err)
+			}
+			mountedPartitions = make(map[string][]MountedPartition)
+			if logs, err := LoadMounts(); err != nil || !strings.Contains(logs, "restaurado") {
+				t.Fatalf("LoadMounts: %q, %v", logs, err)
+			}
+			if got := mountedID(path, "l2"); got != id {
+				t.Errorf("después de LoadMounts el ID es %q, se esperaba %q", got, id)
+			}
+
+			if _, err := Unmount(id); err != nil {
+				t.Fatal(err)
+			}
+			if ebr := logicalEBR(t, path, "l2").EBR; ebr.PartMount != '0' || ebr.PartId != [8]byte{} {
+				t.Errorf("después de unmount: PartMount = %c, PartId = %q", ebr.PartMount, ebr.PartId)
+			}
+		})
+	}
+}
+
+// TestReconcileLogicalID revisa que al cargar la tabla de montajes se descarte la lógica cuyo EBR
+// tiene otro ID que el guardado, y que se libere en el disco
+func TestReconcileLogicalID(t *testing.T) {
+	resetMounts(t)
+	path := newTestDisk(t, "64", testPartition{"ext", "e", 600}, testPartition{"l1", "l", 200})
+	if _, err := Mount(path, "l1"); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := logicalEBR(t, path, "l1")
+	entry.EBR.PartId = [8]byte{}
+	copy(entry.EBR.PartId[:], "0999z")
+	file, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteEBR(file, entry.EBR, entry.Position); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	mountedPartitions = make(map[string][]MountedPartition)
+	logs, err := LoadMounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(logs, "descartado") || !strings.Contains(logs, "0999z") {
+		t.Errorf("LoadMounts no descartó el montaje con otro ID: %q", logs)
+	}
+	if ebr := logicalEBR(t, path, "l1").EBR; ebr.PartMount != '0' || ebr.PartId != [8]byte{} {
+		t.Errorf("la lógica no se liberó: PartMount = %c, PartId = %q", ebr.PartMount, ebr.PartId)
+	}
+}
@@ -0,0 +1,130 @@
+package DiskManagement
+
+import (
+	"backend/Structs"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// partitionRef ubica una partición primaria o lógica dentro del disco
+type partitionRef struct {
+	Partition Structs.Partition // Vista común; en las lógicas tiene tipo 'l' y el inicio y tamaño de sus datos
+	Slot      int               // Slot del MBR o entrada de la GPT; -1 si es lógica
+	EBR       ebrEntry          // EBR de la partición lógica
+	Number    int               // Número para su ID: slot + 1 (entrada + 1 en GPT), o 5, 6, ... para las lógicas en orden
+	ID        string            // ID de montaje guardado en el disco o, en las lógicas de 32 bits, el de la tabla de montajes
+}
+
+// logicalView presenta una partición lógica como una Partition para tratarla igual que a las primarias.
+// En los discos de 32 bits el EBR no tiene ID de montaje y el Id queda vacío.
+func logicalView(ebr Structs.EBR) Structs.Partition {
+	var partition Structs.Partition
+	partition.Status[0] = ebr.PartMount
+	partition.Type[0] = 'l'
+	partition.Fit[0] = ebr.PartFit
+	partition.Start = ebr.PartStart
+	partition.Size = ebr.PartSize
+	partition.Name = ebr.PartName
+	copy(partition.Id[:], ebr.PartId[:])
+	return partition
+}
+
+// findPartition recorre las particiones que pueden montarse (primarias y lógicas, nunca la extendida)
+// y devuelve la primera para la que match es verdadero; nil si ninguna coincide
+func findPartition(file *os.File, table *DiskTable, match func(partitionRef) bool) (*partitionRef, error) {
+	for i, partition := range table.Partitions {
+		if partition.Size == 0 || partition.Type[0] == 'e' {
+			continue
+		}
+		ref := partitionRef{Partition: partition, Slot: i, Number: i + 1, ID: strings.TrimRight(string(partition.Id[:]), "\x00")}
+		if match(ref) {
+			return &ref, nil
+		}
+	}
+
+	for _, extended := range table.Partitions {
+		if extended.Size == 0 || extended.Type[0] != 'e' {
+			continue
+		}
+		chain, err := readEBRChain(file, extended)
+		if err != nil {
+			return nil, err
+		}
+		count := 0
+		for _, entry := range chain {
+			if entry.EBR.PartSize == 0 {
+				continue
+			}
+			view := logicalView(entry.EBR)
+			ref := partitionRef{Partition: view, Slot: -1, EBR: entry, Number: LogicalNumber(count), ID: strings.TrimRight(string(view.Id[:]), "\x00")}
+			if table.MBR.Version == Structs.Format32 {
+				ref.ID = mountedID(file.Name(), strings.TrimRight(string(view.Name[:]), "\x00"))
+			}
+			if match(ref) {
+				return &ref, nil
+			}
+			count++
+		}
+	}
+	return nil, nil
+}
+
+// setMountState guarda el estado y el ID de montaje en la tabla de particiones o, en las lógicas, en su
+// EBR. El EBR de 32 bits conserva el formato original sin campo para el ID y solo guarda el estado.
+func (ref *partitionRef) setMountState(file *os.File, table *DiskTable, status byte, id string) error {
+	if ref.Slot >= 0 {
+		table.Partitions[ref.Slot].Status[0] = status
+		table.Partitions[ref.Slot].Id = [8]byte{}
+		copy(table.Partitions[ref.Slot].Id[:], id)
+		return WriteTable(file, table)
+	}
+	ref.EBR.EBR.PartMount = status
+	ref.EBR.EBR.PartId = [8]byte{}
+	if table.MBR.Version != Structs.Format32 {
+		copy(ref.EBR.EBR.PartId[:], id)
+	}
+	return WriteEBR(file, ref.EBR.EBR, ref.EBR.Position)
+}
+
+// byName coincide con la partición de ese nombre
+func byName(name string) func(partitionRef) bool {
+	return func(ref partitionRef) bool {
+		return strings.TrimRight(string(ref.Partition.Name[:]), "\x00") == name
+	}
+}
+
+// byID coincide con la partición montada con ese ID
+func byID(id string) func(partitionRef) bool {
+	return func(ref partitionRef) bool {
+		return id != "" && SameID(ref.ID, id)
+	}
+}
+
+// mountedID devuelve el ID con el que está montada la partición name del disco path, o "" si no lo está
+func mountedID(path string, name string) string {
+	for _, partition := range mountedPartitions[generateDiskID(path)] {
+		if partition.Name == name {
+			return partition.ID
+		}
+	}
+	return ""
+}
+
+// LocatePartition busca en el disco la partición montada con el ID indicado, ya sea primaria o lógica.
+// Es la búsqueda común para mkfs, login, los archivos y los reportes: de la partición devuelta se usan
+// su inicio (donde está el superbloque), su tamaño y su nombre.
+func LocatePartition(file *os.File, id string) (Structs.Partition, error) {
+	table, err := ReadTable(file)
+	if err != nil {
+		return Structs.Partition{}, fmt.Errorf("no se pudo leer la tabla de particiones: %v", err)
+	}
+	ref, err := findPartition(file, table, byID(id))
+	if err != nil {
+		return Structs.Partition{}, err
+	}
+	if ref == nil {
+		return Structs.Partition{}, fmt.Errorf("no se encontró la partición %s en el disco", id)
+	}
+	return ref.Partition, nil
+}
@@ -0,0 +1,282 @@
+package DiskManagement
+
+import (
+	"backend/Structs"
+	"backend/Utilities"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"os"
+)
+
+// DiskTable es la tabla de particiones de un disco, ya sea el MBR con sus 4 slots o una GPT.
+// Los comandos trabajan con Partitions sin importar el tipo y WriteTable la guarda como corresponde.
+type DiskTable struct {
+	MBR        Structs.MBR         // En un disco GPT solo trae el tamaño, la fecha, la firma, el ajuste y el formato
+	GPT        *Structs.GPTHeader  // Encabezado GPT principal; nil si el disco tiene MBR
+	Partitions []Structs.Partition // Slots del MBR o entradas de la GPT; las vacías tienen Size 0
+	Recovered  bool                // La GPT principal estaba dañada y se leyó la de respaldo
+	guids      [][16]byte          // GUID de cada entrada de la GPT
+}
+
+// Kind devuelve el tipo de la tabla para los mensajes: "mbr" o "gpt"
+func (t *DiskTable) Kind() string {
+	if t.GPT != nil {
+		return "gpt"
+	}
+	return "mbr"
+}
+
+// DataStart y DataEnd delimitan el espacio donde pueden ir las particiones
+func (t *DiskTable) DataStart() int64 {
+	if t.GPT != nil {
+		return t.GPT.FirstUsable
+	}
+	return MBRSize(t.MBR.Version)
+}
+
+func (t *DiskTable) DataEnd() int64 {
+	if t.GPT != nil {
+		return t.GPT.LastUsable + 1
+	}
+	return t.MBR.MbrSize
+}
+
+// Gaps devuelve el mapa de espacio libre del disco a partir de las particiones de la tabla
+func (t *DiskTable) Gaps() []Gap {
+	var used []Gap
+	for _, partition := range t.Partitions {
+		if partition.Size > 0 {
+			used = append(used, Gap{Start: partition.Start, Size: partition.Size})
+		}
+	}
+	return FreeGaps(t.DataStart(), t.DataEnd(), used)
+}
+
+// GUID devuelve el GUID de la entrada i de la GPT; ceros si no tiene o el disco tiene MBR
+func (t *DiskTable) GUID(i int) [16]byte {
+	if i < 0 || i >= len(t.guids) {
+		return [16]byte{}
+	}
+	return t.guids[i]
+}
+
+// GPTHeaderSize y GPTEntrySize dan lo que ocupan el encabezado y cada entrada de la GPT
+func GPTHeaderSize() int64 {
+	return int64(binary.Size(Structs.GPTHeader{}))
+}
+
+func GPTEntrySize() int64 {
+	return int64(binary.Size(Structs.GPTEntry{}))
+}
+
+// GPTReservedSize da lo que ocupa una GPT de entries entradas: los dos encabezados y los dos arreglos
+func GPTReservedSize(entries int) int64 {
+	return 2 * (GPTHeaderSize() + int64(entries)*GPTEntrySize())
+}
+
+// NewGPTTable arma la GPT vacía de un disco a partir de su MBR (tamaño, fecha, firma y ajuste)
+// con espacio para entries particiones
+func NewGPTTable(mbr Structs.MBR, entries int) (*DiskTable, error) {
+	arraySize := int64(entries) * GPTEntrySize()
+	if reserved := GPTReservedSize(entries); mbr.MbrSize <= reserved {
+		return nil, fmt.Errorf("el disco de %d bytes no tiene espacio para una GPT de %d entradas, que ocupa %d bytes con su respaldo", mbr.MbrSize, entries, reserved)
+	}
+	header := Structs.GPTHeader{
+		Tag:           Structs.GPTTag,
+		Version:       Structs.Format64,
+		Signature:     Structs.GPTSignature,
+		MyPosition:    0,
+		AltPosition:   mbr.MbrSize - GPTHeaderSize(),
+		FirstUsable:   GPTHeaderSize() + arraySize,
+		LastUsable:    mbr.MbrSize - GPTHeaderSize() - arraySize - 1,
+		DiskGUID:      newGUID(),
+		EntriesStart:  GPTHeaderSize(),
+		EntryCount:    int32(entries),
+		EntrySize:     int32(GPTEntrySize()),
+		DiskSize:      mbr.MbrSize,
+		CreationDate:  mbr.CreationDate,
+		DiskSignature: mbr.Signature,
+		Fit:           mbr.Fit,
+	}
+	return gptTable(header, make([]Structs.GPTEntry, entries)), nil
+}
+
+// gptTable arma la tabla en memoria a partir del encabezado y las entradas leídas
+func gptTable(header Structs.GPTHeader, entries []Structs.GPTEntry) *DiskTable {
+	table := &DiskTable{GPT: &header}
+	table.MBR = Structs.MBR{Version: Structs.Format64, MbrSize: header.DiskSize, CreationDate: header.CreationDate, Signature: header.DiskSignature, Fit: header.Fit}
+	for _, entry := range entries {
+		table.Partitions = append(table.Partitions, entry.Partition())
+		guid := [16]byte{}
+		if entry.Used() {
+			guid = entry.PartGUID
+		}
+		table.guids = append(table.guids, guid)
+	}
+	return table
+}
+
+// ReadTable lee la tabla de particiones del disco, MBR o GPT
+func ReadTable(file *os.File) (*DiskTable, error) {
+	if !isGPT(file) {
+		mbr, err := ReadMBR(file)
+		if err != nil {
+			return nil, err
+		}
+		return &DiskTable{MBR: mbr, Partitions: append([]Structs.Partition(nil), mbr.Partitions[:]...)}, nil
+	}
+
+	header, entries, recovered, err := readGPT(file)
+	if err != nil {
+		return nil, err
+	}
+	table := gptTable(header, entries)
+	table.Recovered = recovered
+	return table, nil
+}
+
+// WriteTable guarda la tabla en el disco. En GPT se escriben los dos arreglos de entradas y los dos
+// encabezados con sus CRC32, primero el respaldo; así una GPT principal dañada queda reparada.
+func WriteTable(file *os.File, table *DiskTable) error {
+	if table.GPT == nil {
+		if len(table.Partitions) != len(table.MBR.Partitions) {
+			return fmt.Errorf("el MBR tiene %d slots, no %d", len(table.MBR.Partitions), len(table.Partitions))
+		}
+		copy(table.MBR.Partitions[:], table.Partitions)
+		return WriteMBR(file, table.MBR)
+	}
+
+	entries := make([]Structs.GPTEntry, len(table.Partitions))
+	for i, partition := range table.Partitions {
+		if partition.Size == 0 {
+			table.guids[i] = [16]byte{}
+			continue
+		}
+		if table.guids[i] == [16]byte{} {
+			table.guids[i] = newGUID()
+		}
+		entries[i] = Structs.NewGPTEntry(partition, table.guids[i])
+	}
+
+	primary := *table.GPT
+	primary.EntriesCRC = Structs.ChecksumGPT(entries)
+	backup := primary
+	backup.MyPosition, backup.AltPosition = primary.AltPosition, primary.MyPosition
+	backup.EntriesStart = primary.LastUsable + 1
+	primary.HeaderCRC = primary.HeaderChecksum()
+	backup.HeaderCRC = backup.HeaderChecksum()
+
+	if err := Utilities.WriteObject(file, entries, backup.EntriesStart); err != nil {
+		return err
+	}
+	if err := Utilities.WriteObject(file, backup, backup.MyPosition); err != nil {
+		return err
+	}
+	if err := Utilities.WriteObject(file, entries, primary.EntriesStart); err != nil {
+		return err
+	}
+	if err := Utilities.WriteObject(file, primary, primary.MyPosition); err != nil {
+		return err
+	}
+	*table.GPT = primary
+	table.Recovered = false
+	return nil
+}
+
+// isGPT indica si el disco tiene una GPT: por la marca al inicio o, si el encabezado principal está
+// dañado, porque en los últimos bytes hay un encabezado de respaldo válido
+func isGPT(file *os.File) bool {
+	var tag int32
+	if err := Utilities.ReadObject(file, &tag, 0); err == nil && tag == Structs.GPTTag {
+		return true
+	}
+	if tag == Structs.FormatTag {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil || info.Size() < GPTHeaderSize() {
+		return false
+	}
+	var header Structs.GPTHeader
+	if err := Utilities.ReadObject(file, &header, info.Size()-GPTHeaderSize()); err != nil {
+		return false
+	}
+	return header.Signature == Structs.GPTSignature && header.HeaderCRC == header.HeaderChecksum()
+}
+
+// readGPT lee el encabezado y las entradas principales; si alguno está dañado usa el respaldo.
+// Lo leído del respaldo se devuelve como encabezado principal para que WriteTable repare el disco.
+func readGPT(file *os.File) (Structs.GPTHeader, []Structs.GPTEntry, bool, error) {
+	primary, err := readGPTHeader(file, 0)
+	if err == nil {
+		entries, entriesErr := readGPTEntries(file, primary)
+		if entriesErr == nil {
+			return primary, entries, false, nil
+		}
+		err = entriesErr
+	}
+
+	info, statErr := file.Stat()
+	if statErr != nil {
+		return Structs.GPTHeader{}, nil, false, statErr
+	}
+	position := info.Size() - GPTHeaderSize()
+	backup, backupErr := readGPTHeader(file, position)
+	if backupErr != nil {
+		return backup, nil, false, fmt.Errorf("la GPT principal (%v) y la de respaldo (%v) están dañadas", err, backupErr)
+	}
+	entries, backupErr := readGPTEntries(file, backup)
+	if backupErr != nil {
+		return backup, nil, false, fmt.Errorf("la GPT principal (%v) y la de respaldo (%v) están dañadas", err, backupErr)
+	}
+
+	header := backup
+	header.MyPosition, header.AltPosition = backup.AltPosition, backup.MyPosition
+	header.EntriesStart = header.MyPosition + GPTHeaderSize()
+	return header, entries, true, nil
+}
+
+// readGPTHeader lee el encabezado GPT de position y verifica su firma, su CRC32 y su posición
+func readGPTHeader(file *os.File, position int64) (Structs.GPTHeader, error) {
+	var header Structs.GPTHeader
+	if err := Utilities.ReadObject(file, &header, position); err != nil {
+		return header, fmt.Errorf("no se pudo leer el encabezado en la posición %d", position)
+	}
+	if header.Signature != Structs.GPTSignature || header.Tag != Structs.GPTTag {
+		return header, fmt.Errorf("no hay un encabezado GPT en la posición %d", position)
+	}
+	if header.HeaderCRC != header.HeaderChecksum() {
+		return header, fmt.Errorf("el CRC32 del encabezado en la posición %d no coincide", position)
+	}
+	if header.MyPosition != position || header.EntryCount <= 0 || int64(header.EntrySize) != GPTEntrySize() {
+		return header, fmt.Errorf("el encabezado en la posición %d no es consistente", position)
+	}
+	return header, nil
+}
+
+// readGPTEntries lee el arreglo de entradas del encabezado y verifica su CRC32
+func readGPTEntries(file *os.File, header Structs.GPTHeader) ([]Structs.GPTEntry, error) {
+	entries := make([]Structs.GPTEntry, header.EntryCount)
+	if err := Utilities.ReadObject(file, entries, header.EntriesStart); err != nil {
+		return nil, fmt.Errorf("no se pudieron leer las entradas en la posición %d", header.EntriesStart)
+	}
+	if Structs.ChecksumGPT(entries) != header.EntriesCRC {
+		return nil, fmt.Errorf("el CRC32 de las entradas en la posición %d no coincide", header.EntriesStart)
+	}
+	return entries, nil
+}
+
+// newGUID genera un GUID aleatorio (versión 4)
+func newGUID() [16]byte {
+	var guid [16]byte
+	rand.Read(guid[:])
+	guid[6] = guid[6]&0x0f | 0x40
+	guid[8] = guid[8]&0x3f | 0x80
+	return guid
+}
+
+// FormatGUID escribe el GUID en su forma de texto habitual
+func FormatGUID(guid [16]byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", guid[0:4], guid[4:6], guid[6:8], guid[8:10], guid[10:16])
+}
@@ -0,0 +1,245 @@
+package DiskManagement
+
+import (
+	"backend/Utilities"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Papelera de discos: rmdisk mueve el disco a la carpeta trash dentro de la carpeta de datos, con un
+// archivo .json que dice de dónde vino. restoredisk lo devuelve a su ruta y PurgeTrash elimina de
+// forma definitiva lo que lleva en la papelera más que la retención configurada.
+
+// TrashRetentionEnv es la variable de entorno con la retención de la papelera
+const TrashRetentionEnv = "MIA_TRASH_RETENTION"
+
+// DefaultTrashRetention es cuánto se conserva un disco eliminado si no se configura otra cosa
+const DefaultTrashRetention = 7 * 24 * time.Hour
+
+// Carpeta de la papelera dentro de la carpeta de datos
+const trashDirName = "trash"
+
+// TrashEntry describe un disco en la papelera; se guarda como <ID>.json junto a <ID>.mia
+type TrashEntry struct {
+	ID        string    `json:"id"`
+	Path      string    `json:"path"` // Ruta original del disco
+	Size      int64     `json:"size"`
+	DeletedAt time.Time `json:"deleted_at"`
+	Unmounted []string  `json:"unmounted,omitempty"` // IDs que se desmontaron con -force
+}
+
+// File devuelve la ruta del disco dentro de la papelera
+func (e TrashEntry) File() string {
+	return filepath.Join(trashPath(), e.ID+".mia")
+}
+
+func (e TrashEntry) metadataFile() string {
+	return filepath.Join(trashPath(), e.ID+".json")
+}
+
+// trashPath devuelve la carpeta de la papelera
+func trashPath() string {
+	return filepath.Join(Utilities.DataDir(), trashDirName)
+}
+
+// TrashRetention devuelve la retención configurada en MIA_TRASH_RETENTION: una duración de Go
+// (36h, 90m) o días con el sufijo d (7d). Con 0 los discos se conservan hasta restaurarlos.
+func TrashRetention() (time.Duration, error) {
+	value := strings.TrimSpace(os.Getenv(TrashRetentionEnv))
+	if value == "" {
+		return DefaultTrashRetention, nil
+	}
+	var retention time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		var count int
+		count, err = strconv.Atoi(days)
+		retention = time.Duration(count) * 24 * time.Hour
+	} else {
+		retention, err = time.ParseDuration(value)
+	}
+	if err != nil || retention < 0 {
+		return 0, fmt.Errorf("%s inválido '%s': se espera una duración como 7d, 36h o 0", TrashRetentionEnv, value)
+	}
+	return retention, nil
+}
+
+// formatRetention muestra la retención en días cuando es un número exacto de días
+func formatRetention(retention time.Duration) string {
+	days := retention / (24 * time.Hour)
+	switch {
+	case retention%(24*time.Hour) != 0:
+		return retention.String()
+	case days == 1:
+		return "1 día"
+	default:
+		return fmt.Sprintf("%d días", days)
+	}
+}
+
+// TrashEntries devuelve los discos de la papelera, del más antiguo al más reciente
+func TrashEntries() ([]TrashEntry, error) {
+	files, err := filepath.Glob(filepath.Join(trashPath(), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var entries []TrashEntry
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var entry TrashEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("entrada inválida en la papelera %s: %v", file, err)
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].DeletedAt.Before(entries[j].DeletedAt) })
+	return entries, nil
+}
+
+// FindTrashEntry busca en la papelera el disco que estaba en path: el más reciente o, si id no
+// está vacío, esa entrada
+func FindTrashEntry(path string, id string) (TrashEntry, error) {
+	entries, err := TrashEntries()
+	if err != nil {
+		return TrashEntry{}, err
+	}
+	var found []TrashEntry
+	for _, entry := range entries {
+		if generateDiskID(entry.Path) == generateDiskID(path) {
+			found = append(found, entry)
+		}
+	}
+	if len(found) == 0 {
+		return TrashEntry{}, fmt.Errorf("no hay ningún disco de %s en la papelera", path)
+	}
+	if id == "" {
+		return found[len(found)-1], nil
+	}
+	var ids []string
+	for _, entry := range found {
+		if entry.ID == id {
+			return entry, nil
+		}
+		ids = append(ids, entry.ID)
+	}
+	return TrashEntry{}, fmt.Errorf("la papelera no tiene la entrada %s de %s; entradas disponibles: %s", id, path, strings.Join(ids, ", "))
+}
+
+// moveToTrash mueve el disco a la papelera y guarda su entrada
+func moveToTrash(path string, unmounted []string) (TrashEntry, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return TrashEntry{}, err
+	}
+	if err := os.MkdirAll(trashPath(), os.ModePerm); err != nil {
+		return TrashEntry{}, err
+	}
+
+	// El ID es la fecha y el nombre del disco; si ya existe se agrega un número
+	now := time.Now()
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	entry := TrashEntry{ID: now.Format("20060102-150405") + "-" + base, Path: path, Size: info.Size(), DeletedAt: now, Unmounted: unmounted}
+	for n := 2; ; n++ {
+		if _, err := os.Stat(entry.metadataFile()); os.IsNotExist(err) {
+			break
+		}
+		entry.ID = fmt.Sprintf("%s-%s-%d", now.Format("20060102-150405"), base, n)
+	}
+
+	// La entrada se escribe antes de mover el disco: si algo falla a la mitad, nunca queda en la
+	// papelera un disco sin su entrada, que nadie podría restaurar ni purgar
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return entry, err
+	}
+	// CreateFile registra la entrada como archivo nuevo para que una transacción la borre al deshacer
+	if err := Utilities.CreateFile(entry.metadataFile()); err != nil {
+		return entry, err
+	}
+	if err := os.WriteFile(entry.metadataFile(), data, 0644); err != nil {
+		os.Remove(entry.metadataFile())
+		return entry, err
+	}
+	if err := Utilities.MoveFile(path, entry.File()); err != nil {
+		os.Remove(entry.metadataFile())
+		return entry, err
+	}
+	return entry, nil
+}
+
+// PurgeTrash elimina de forma definitiva los discos que llevan en la papelera más que la retención.
+// Devuelve una línea por cada disco eliminado.
+func PurgeTrash() (string, error) {
+	retention, err := TrashRetention()
+	if err != nil || retention == 0 {
+		return "", err
+	}
+	entries, err := TrashEntries()
+	if err != nil {
+		return "", err
+	}
+
+	var logs string
+	for _, entry := range entries {
+		if time.Since(entry.DeletedAt) < retention {
+			continue
+		}
+		if err := Utilities.RemoveFile(entry.File()); err != nil && !os.IsNotExist(err) {
+			return logs, fmt.Errorf("no se pudo purgar %s de la papelera: %v", entry.ID, err)
+		}
+		if err := Utilities.RemoveFile(entry.metadataFile()); err != nil {
+			return logs, fmt.Errorf("no se pudo purgar %s de la papelera: %v", entry.ID, err)
+		}
+		logs += fmt.Sprintf("Disco %s purgado de la papelera (eliminado el %s)\n", entry.Path, entry.DeletedAt.Format("2006-01-02 15:04"))
+	}
+	return logs, nil
+}
+
+// RestoreDisk devuelve a su ruta el disco de path que está en la papelera: el eliminado más
+// recientemente o la entrada id
+func RestoreDisk(path string, id string) (string, error) {
+	var logs string
+	logs += "======Start RESTOREDISK======\n"
+	logs += fmt.Sprintf("Path: %s\n", path)
+
+	entry, err := FindTrashEntry(path, id)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	if _, err := os.Stat(entry.Path); err == nil {
+		errMsg := fmt.Sprintf("Error: Ya existe un disco en %s; muévalo o elimínelo antes de restaurar.", entry.Path)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	if err := Utilities.CreateParentDirs(entry.Path); err != nil {
+		errMsg := fmt.Sprintf("Error: No se pudo crear la carpeta del disco: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	if err := Utilities.MoveFile(entry.File(), entry.Path); err != nil {
+		errMsg := fmt.Sprintf("Error: No se pudo restaurar el disco: %v", err)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
+	if err := Utilities.RemoveFile(entry.metadataFile()); err != nil {
+		logs += fmt.Sprintf("No se pudo borrar la entrada %s de la papelera: %v\n", entry.ID, err)
+	}
+
+	logs += fmt.Sprintf("Entrada: %s, eliminado el %s, %d bytes\n", entry.ID, entry.DeletedAt.Format("2006-01-02 15:04:05"), entry.Size)
+	if len(entry.Unmounted) > 0 {
+		logs += fmt.Sprintf("Al eliminarlo se desmontaron %s; vuelva a montarlas si las necesita\n", strings.Join(entry.Unmounted, ", "))
+	}
+	logs += "======FIN RESTOREDISK======\n"
+	return logs + fmt.Sprintf("RESTOREDISK: Disco %s restaurado desde la papelera", entry.Path), nil
+}
@@ -8,9 +8,8 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"math"
 	"os"
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
 )
@@ -32,7 +31,7 @@ func Mkfs(id string, type_ string, fs_ string) (string, error) {
 
 	for _, partitions := range DiskManagement.GetMountedPartitions() {
 		for _, partition := range partitions {
-			if partition.ID == id {
+			if DiskManagement.SameID(partition.ID, id) {
 				mountedPartition = partition
 				partitionFound = true
 				break
@@ -64,43 +63,35 @@ func Mkfs(id string, type_ string, fs_ string) (string, error) {
 	}
 	defer file.Close()
 
-	var TempMBR Structs.MBR
 	// Leer objeto desde archivo binario
-	if err := Utilities.ReadObject(file, &TempMBR, 0); err != nil {
+	table, err := DiskManagement.ReadTable(file)
+	if err != nil {
 		errMsg := "Error al leer MBR del archivo"
 		logs += errMsg + "\n"
 		return logs, fmt.Errorf(errMsg)
 	}
 
-	// Formatear el MBR y agregarlo al log
-	logs += fmt.Sprintf("MBR Size: %d\n", TempMBR.MbrSize)
-	logs += fmt.Sprintf("MBR Signature: %d\n", TempMBR.Signature)
-	logs += fmt.Sprintf("MBR Fit: %s\n", string(TempMBR.Fit[:]))
-	logs += fmt.Sprintf("MBR Creation Date: %s\n", string(TempMBR.CreationDate[:]))
+	// Formatear el MBR (o los datos equivalentes de la GPT) y agregarlo al log
+	logs += fmt.Sprintf("MBR Size: %d\n", table.MBR.MbrSize)
+	logs += fmt.Sprintf("MBR Signature: %d\n", table.MBR.Signature)
+	logs += fmt.Sprintf("MBR Fit: %s\n", string(table.MBR.Fit[:]))
+	logs += fmt.Sprintf("MBR Creation Date: %s\n", string(table.MBR.CreationDate[:]))
 	logs += "-------------\n"
 
-	var index int = -1
-	// Iterar sobre las particiones para encontrar la que tiene el nombre correspondiente
-	for i := 0; i < 4; i++ {
-		if TempMBR.Partitions[i].Size != 0 {
-			if strings.Contains(string(TempMBR.Partitions[i].Id[:]), id) {
-				index = i
-				break
-			}
-		}
-	}
-
-	if index != -1 {
-		logs += fmt.Sprintf("Partición encontrada: %s\n", string(TempMBR.Partitions[index].Name[:]))
-	} else {
+	// Buscar la partición (primaria o lógica) con el ID indicado
+	partition, err := DiskManagement.LocatePartition(file, id)
+	if err != nil {
 		errMsg := "Partición no encontrada (2)"
 		logs += errMsg + "\n"
 		return logs, fmt.Errorf(errMsg)
 	}
+	logs += fmt.Sprintf("Partición encontrada: %s\n", string(partition.Name[:]))
 
-	numerador := int32(TempMBR.Partitions[index].Size - int32(binary.Size(Structs.Superblock{})))
-	denominador_base := int32(4 + int32(binary.Size(Structs.Inode{})) + 3*int32(binary.Size(Structs.Fileblock{})))
-	var temp int32 = 0
+	// El superbloque ocupa más o menos según el formato del disco
+	superblockSize := DiskManagement.SuperblockSize(table.MBR.Version)
+	numerador := partition.Size - superblockSize
+	denominador_base := int64(4 + binary.Size(Structs.Inode{}) + 3*binary.Size(Structs.Fileblock{}))
+	var temp int64 = 0
 	if fs_ == "2fs" {
 		temp = 0
 	} else {
@@ -109,6 +100,11 @@ func Mkfs(id string, type_ string, fs_ string) (string, error) {
 		return logs, fmt.Errorf(errMsg)
 	}
 	denominador := denominador_base + temp
+	if numerador/denominador > math.MaxInt32/3 {
+		errMsg := fmt.Sprintf("Error: La partición es demasiado grande para EXT2 (%d inodos)", numerador/denominador)
+		logs += errMsg + "\n"
+		return logs, fmt.Errorf(errMsg)
+	}
 	n := int32(numerador / denominador)
 
 	logs += fmt.Sprintf("INODOS: %d\n", n)
@@ -131,14 +127,14 @@ func Mkfs(id string, type_ string, fs_ string) (string, error) {
 	newSuperblock.S_block_size = int32(binary.Size(Structs.Fileblock{}))
 
 	// Calcula las posiciones de inicio
-	newSuperblock.S_bm_inode_start = TempMBR.Partitions[index].Start + int32(binary.Size(Structs.Superblock{}))
-	newSuperblock.S_bm_block_start = newSuperblock.S_bm_inode_start + n
-	newSuperblock.S_inode_start = newSuperblock.S_bm_block_start + 3*n
-	newSuperblock.S_block_start = newSuperblock.S_inode_start + n*newSuperblock.S_inode_size
+	newSuperblock.S_bm_inode_start = partition.Start + superblockSize
+	newSuperblock.S_bm_block_start = newSuperblock.S_bm_inode_start + int64(n)
+	newSuperblock.S_inode_start = newSuperblock.S_bm_block_start + 3*int64(n)
+	newSuperblock.S_block_start = newSuperblock.S_inode_start + int64(n)*int64(newSuperblock.S_inode_size)
 
 	if fs_ == "2fs" {
 		logs += "Creando EXT2...\n"
-		create_ext2(n, TempMBR.Partitions[index], newSuperblock, currentDate, file)
+		create_ext2(n, partition, newSuperblock, currentDate, file)
 	} else {
 		errMsg := "EXT3 no está soportado."
 		logs += errMsg + "\n"
@@ -158,19 +154,24 @@ func create_ext2(n int32, partition Structs.Partition, newSuperblock Structs.Sup
 	Structs.PrintSuperblock(newSuperblock)
 	fmt.Println("Date:", date)
 
+	// El progreso se mide sobre todo lo que se escribe: bitmaps (n + 3n), inodos (n) y bloques (3n)
+	total := int64(8 * n)
+
 	// Escribe los bitmaps de inodos y bloques en el archivo
 	for i := int32(0); i < n; i++ {
-		if err := Utilities.WriteObject(file, byte(0), int64(newSuperblock.S_bm_inode_start+i)); err != nil {
+		if err := Utilities.WriteObject(file, byte(0), newSuperblock.S_bm_inode_start+int64(i)); err != nil {
 			fmt.Println("Error: ", err)
 			return
 		}
+		Utilities.ReportProgress("create_ext2", int64(i+1), total)
 	}
 
 	for i := int32(0); i < 3*n; i++ {
-		if err := Utilities.WriteObject(file, byte(0), int64(newSuperblock.S_bm_block_start+i)); err != nil {
+		if err := Utilities.WriteObject(file, byte(0), newSuperblock.S_bm_block_start+int64(i)); err != nil {
 			fmt.Println("Error: ", err)
 			return
 		}
+		Utilities.ReportProgress("create_ext2", int64(n+i+1), total)
 	}
 
 	// Inicializa inodos y bloques con valores predeterminados
@@ -186,7 +187,7 @@ func create_ext2(n int32, partition Structs.Partition, newSuperblock Structs.Sup
 	}
 
 	// Escribe el superbloque actualizado al archivo
-	if err := Utilities.WriteObject(file, newSuperblock, int64(partition.Start)); err != nil {
+	if err := DiskManagement.WriteSuperblock(file, newSuperblock, partition.Start); err != nil {
 		fmt.Println("Error: ", err)
 		return
 	}
@@ -201,7 +202,7 @@ func create_ext2(n int32, partition Structs.Partition, newSuperblock Structs.Sup
 	fmt.Println("====== Imprimiendo Inodos ======")
 	for i := int32(0); i < n; i++ {
 		var inode Structs.Inode
-		offset := int64(newSuperblock.S_inode_start + i*int32(binary.Size(Structs.Inode{})))
+		offset := newSuperblock.S_inode_start + int64(i)*int64(binary.Size(Structs.Inode{}))
 		if err := Utilities.ReadObject(file, &inode, offset); err != nil {
 			fmt.Println("Error al leer inodo: ", err)
 			return
@@ -228,7 +229,7 @@ func create_ext2(n int32, partition Structs.Partition, newSuperblock Structs.Sup
 	// Imprimir Folderblocks
 	for i := int32(0); i < 1; i++ {
 		var folderblock Structs.Folderblock
-		offset := int64(newSuperblock.S_block_start + i*int32(binary.Size(Structs.Folderblock{})))
+		offset := newSuperblock.S_block_start + int64(i)*int64(binary.Size(Structs.Folderblock{}))
 		if err := Utilities.ReadObject(file, &folderblock, offset); err != nil {
 			fmt.Println("Error al leer Folderblock: ", err)
 			return
@@ -239,7 +240,7 @@ func create_ext2(n int32, partition Structs.Partition, newSuperblock Structs.Sup
 	// Imprimir Fileblocks
 	for i := int32(0); i < 1; i++ {
 		var fileblock Structs.Fileblock
-		offset := int64(newSuperblock.S_block_start + int32(binary.Size(Structs.Folderblock{})) + i*int32(binary.Size(Structs.Fileblock{})))
+		offset := newSuperblock.S_block_start + int64(binary.Size(Structs.Folderblock{})) + int64(i)*int64(binary.Size(Structs.Fileblock{}))
 		if err := Utilities.ReadObject(file, &fileblock, offset); err != nil {
 			fmt.Println("Error al leer Fileblock: ", err)
 			return
@@ -262,17 +263,21 @@ func initInodesAndBlocks(n int32, newSuperblock Structs.Superblock, file *os.Fil
 		newInode.I_block[i] = -1
 	}
 
+	// Los bitmaps ya ocuparon 4n del progreso de create_ext2
+	total := int64(8 * n)
 	for i := int32(0); i < n; i++ {
-		if err := Utilities.WriteObject(file, newInode, int64(newSuperblock.S_inode_start+i*int32(binary.Size(Structs.Inode{})))); err != nil {
+		if err := Utilities.WriteObject(file, newInode, newSuperblock.S_inode_start+int64(i)*int64(binary.Size(Structs.Inode{}))); err != nil {
 			return err
 		}
+		Utilities.ReportProgress("create_ext2", int64(4*n+i+1), total)
 	}
 
 	var newFileblock Structs.Fileblock
 	for i := int32(0); i < 3*n; i++ {
-		if err := Utilities.WriteObject(file, newFileblock, int64(newSuperblock.S_block_start+i*int32(binary.Size(Structs.Fileblock{})))); err != nil {
+		if err := Utilities.WriteObject(file, newFileblock, newSuperblock.S_block_start+int64(i)*int64(binary.Size(Structs.Fileblock{}))); err != nil {
 			return err
 		}
+		Utilities.ReportProgress("create_ext2", int64(5*n+i+1), total)
 	}
 
 	return nil
@@ -286,7 +291,7 @@ func ObtenerInodosDesdeSistema(newSuperblock Structs.Superblock, file *os.File)
 	// Leer cada inodo desde su posición calculada
 	for i := int32(0); i < n; i++ {
 		var inode Structs.Inode
-		offset := int64(newSuperblock.S_inode_start + i*int32(binary.Size(Structs.Inode{})))
+		offset := newSuperblock.S_inode_start + int64(i)*int64(binary.Size(Structs.Inode{}))
 		if err := Utilities.ReadObject(file, &inode, offset); err != nil {
 			return nil, fmt.Errorf("error al leer inodo: %v", err)
 		}
@@ -325,13 +330,13 @@ func createRootAndUsersFile(newSuperblock Structs.Superblock, date string, file
 	if err := Utilities.WriteObject(file, Inode0, int64(newSuperblock.S_inode_start)); err != nil {
 		return err
 	}
-	if err := Utilities.WriteObject(file, Inode1, int64(newSuperblock.S_inode_start+int32(binary.Size(Structs.Inode{})))); err != nil {
+	if err := Utilities.WriteObject(file, Inode1, newSuperblock.S_inode_start+int64(binary.Size(Structs.Inode{}))); err != nil {
 		return err
 	}
 	if err := Utilities.WriteObject(file, Folderblock0, int64(newSuperblock.S_block_start)); err != nil {
 		return err
 	}
-	if err := Utilities.WriteObject(file, Fileblock1, int64(newSuperblock.S_block_start+int32(binary.Size(Structs.Folderblock{})))); err != nil {
+	if err := Utilities.WriteObject(file, Fileblock1, newSuperblock.S_block_start+int64(binary.Size(Structs.Folderblock{}))); err != nil {
 		return err
 	}
 
@@ -377,7 +382,7 @@ func printInodes(n int32, newSuperblock Structs.Superblock, file *os.File) {
 	fmt.Println("====== Imprimiendo Inodos ======")
 	for i := int32(0); i < n; i++ {
 		var inode Structs.Inode
-		offset := int64(newSuperblock.S_inode_start + i*int32(binary.Size(Structs.Inode{})))
+		offset := newSuperblock.S_inode_start + int64(i)*int64(binary.Size(Structs.Inode{}))
 		if err := Utilities.ReadObject(file, &inode, offset); err != nil {
 			fmt.Println("Error al leer inodo: ", err)
 			return
@@ -401,7 +406,7 @@ func printBlocks(newSuperblock Structs.Superblock, file *os.File) {
 
 	for i := int32(0); i < 1; i++ {
 		var folderblock Structs.Folderblock
-		offset := int64(newSuperblock.S_block_start + i*int32(binary.Size(Structs.Folderblock{})))
+		offset := newSuperblock.S_block_start + int64(i)*int64(binary.Size(Structs.Folderblock{}))
 		if err := Utilities.ReadObject(file, &folderblock, offset); err != nil {
 			fmt.Println("Error al leer Folderblock: ", err)
 			return
@@ -411,7 +416,7 @@ func printBlocks(newSuperblock Structs.Superblock, file *os.File) {
 
 	for i := int32(0); i < 1; i++ {
 		var fileblock Structs.Fileblock
-		offset := int64(newSuperblock.S_block_start + int32(binary.Size(Structs.Folderblock{})) + i*int32(binary.Size(Structs.Fileblock{})))
+		offset := newSuperblock.S_block_start + int64(binary.Size(Structs.Folderblock{})) + int64(i)*int64(binary.Size(Structs.Fileblock{}))
 		if err := Utilities.ReadObject(file, &fileblock, offset); err != nil {
 			fmt.Println("Error al leer Fileblock: ", err)
 			return
@@ -442,7 +447,7 @@ func Mkdir(path string) (string, error) {
 
 	for _, partitions := range DiskManagement.GetMountedPartitions() {
 		for _, partition := range partitions {
-			if partition.ID == User.CurrentLoggedPartitionID {
+			if DiskManagement.SameID(partition.ID, User.CurrentLoggedPartitionID) {
 				mountedPartition = partition
 				partitionFound = true
 				break
@@ -474,43 +479,33 @@ func Mkdir(path string) (string, error) {
 	}
 	defer file.Close()
 
-	var TempMBR Structs.MBR
 	// Leer objeto desde archivo binario
-	if err := Utilities.ReadObject(file, &TempMBR, 0); err != nil {
+	table, err := DiskManagement.ReadTable(file)
+	if err != nil {
 		errMsg := "Error al leer MBR del archivo"
 		logs += errMsg + "\n"
 		return logs, fmt.Errorf(errMsg)
 	}
 
-	// Formatear el MBR y agregarlo al log
-	logs += fmt.Sprintf("MBR Size: %d\n", TempMBR.MbrSize)
-	logs += fmt.Sprintf("MBR Signature: %d\n", TempMBR.Signature)
-	logs += fmt.Sprintf("MBR Fit: %s\n", string(TempMBR.Fit[:]))
-	logs += fmt.Sprintf("MBR Creation Date: %s\n", string(TempMBR.CreationDate[:]))
+	// Formatear el MBR (o los datos equivalentes de la GPT) y agregarlo al log
+	logs += fmt.Sprintf("MBR Size: %d\n", table.MBR.MbrSize)
+	logs += fmt.Sprintf("MBR Signature: %d\n", table.MBR.Signature)
+	logs += fmt.Sprintf("MBR Fit: %s\n", string(table.MBR.Fit[:]))
+	logs += fmt.Sprintf("MBR Creation Date: %s\n", string(table.MBR.CreationDate[:]))
 	logs += "-------------\n"
 
-	var index int = -1
-	// Iterar sobre las particiones para encontrar la que tiene el nombre correspondiente
-	for i := 0; i < 4; i++ {
-		if TempMBR.Partitions[i].Size != 0 {
-			if strings.Contains(string(TempMBR.Partitions[i].Id[:]), User.CurrentLoggedPartitionID) {
-				index = i
-				break
-			}
-		}
-	}
-
-	if index != -1 {
-		logs += fmt.Sprintf("Partición encontrada: %s\n", string(TempMBR.Partitions[index].Name[:]))
-	} else {
+	// Buscar la partición (primaria o lógica) con la sesión activa
+	partition, err := DiskManagement.LocatePartition(file, User.CurrentLoggedPartitionID)
+	if err != nil {
 		errMsg := "Partición no encontrada (2)"
 		logs += errMsg + "\n"
 		return logs, fmt.Errorf(errMsg)
 	}
+	logs += fmt.Sprintf("Partición encontrada: %s\n", string(partition.Name[:]))
 
 	// Leer el superbloque
-	var superblock Structs.Superblock
-	if err := Utilities.ReadObject(file, &superblock, int64(TempMBR.Partitions[index].Start)); err != nil {
+	superblock, err := DiskManagement.ReadSuperblock(file, partition.Start)
+	if err != nil {
 		errMsg := "Error al leer el superbloque"
 		logs += errMsg + "\n"
 		return logs, fmt.Errorf(errMsg)
@@ -543,14 +538,14 @@ func Mkdir(path string) (string, error) {
 	}
 
 	logs += "======FIN MKDIR======\n"
-	fmt.Println("Directorio creado-------------: %s", path)
+	fmt.Printf("Directorio creado-------------: %s\n", path)
 	ListDirectories()
 	return logs + fmt.Sprintf("Directorio creado: %s", path), nil
 }
 
 func findDirectory(name string, parentInode int32, file *os.File, superblock Structs.Superblock) (bool, int32) {
 	var inode Structs.Inode
-	offset := int64(superblock.S_inode_start + parentInode*int32(binary.Size(Structs.Inode{})))
+	offset := superblock.S_inode_start + int64(parentInode)*int64(binary.Size(Structs.Inode{}))
 	if err := Utilities.ReadObject(file, &inode, offset); err != nil {
 		return false, -1
 	}
@@ -561,7 +556,7 @@ func findDirectory(name string, parentInode int32, file *os.File, superblock Str
 		}
 
 		var folderblock Structs.Folderblock
-		offset := int64(superblock.S_block_start + block*int32(binary.Size(Structs.Folderblock{})))
+		offset := superblock.S_block_start + int64(block)*int64(binary.Size(Structs.Folderblock{}))
 		if err := Utilities.ReadObject(file, &folderblock, offset); err != nil {
 			return false, -1
 		}
@@ -598,7 +593,7 @@ func createDirectory(name string, parentInode int32, file *os.File, superblock S
 	newInode.I_block[0] = newBlockIndex
 
 	// Escribe el nuevo inodo en el archivo en la posición correcta
-	inodeOffset := int64(superblock.S_inode_start + newInodeIndex*int32(binary.Size(Structs.Inode{})))
+	inodeOffset := superblock.S_inode_start + int64(newInodeIndex)*int64(binary.Size(Structs.Inode{}))
 	if err := Utilities.WriteObject(file, newInode, inodeOffset); err != nil {
 		return -1, fmt.Errorf("error al escribir el nuevo inodo: %v", err)
 	}
@@ -608,7 +603,7 @@ func createDirectory(name string, parentInode int32, file *os.File, superblock S
 	newFolderblock.B_content[0].B_inodo = newInodeIndex
 	copy(newFolderblock.B_content[0].B_name[:], name)
 
-	blockOffset := int64(superblock.S_block_start + newBlockIndex*int32(binary.Size(Structs.Folderblock{})))
+	blockOffset := superblock.S_block_start + int64(newBlockIndex)*int64(binary.Size(Structs.Folderblock{}))
 	if err := Utilities.WriteObject(file, newFolderblock, blockOffset); err != nil {
 		return -1, fmt.Errorf("error al escribir el folderblock: %v", err)
 	}
@@ -632,7 +627,7 @@ func createDirectory(name string, parentInode int32, file *os.File, superblock S
 func findFreeInode(superblock Structs.Superblock, file *os.File) int32 {
 	for i := int32(0); i < superblock.S_inodes_count; i++ {
 		var status byte
-		offset := int64(superblock.S_bm_inode_start + i)
+		offset := superblock.S_bm_inode_start + int64(i)
 		if err := Utilities.ReadObject(file, &status, offset); err != nil {
 			return -1
 		}
@@ -646,7 +641,7 @@ func findFreeInode(superblock Structs.Superblock, file *os.File) int32 {
 func findFreeBlock(superblock Structs.Superblock, file *os.File) int32 {
 	for i := int32(0); i < superblock.S_blocks_count; i++ {
 		var status byte
-		offset := int64(superblock.S_bm_block_start + i)
+		offset := superblock.S_bm_block_start + int64(i)
 		if err := Utilities.ReadObject(file, &status, offset); err != nil {
 			return -1
 		}
@@ -659,7 +654,7 @@ func findFreeBlock(superblock Structs.Superblock, file *os.File) int32 {
 
 func updateParentFolderblock(name string, parentInode int32, newInodeIndex int32, file *os.File, superblock Structs.Superblock) error {
 	var inode Structs.Inode
-	inodeOffset := int64(superblock.S_inode_start + parentInode*int32(binary.Size(Structs.Inode{})))
+	inodeOffset := superblock.S_inode_start + int64(parentInode)*int64(binary.Size(Structs.Inode{}))
 	if err := Utilities.ReadObject(file, &inode, inodeOffset); err != nil {
 		return fmt.Errorf("error al leer el inodo padre: %v", err)
 	}
@@ -679,7 +674,7 @@ func updateParentFolderblock(name string, parentInode int32, newInodeIndex int32
 			newFolderblock.B_content[0].B_inodo = newInodeIndex
 			copy(newFolderblock.B_content[0].B_name[:], name)
 
-			blockOffset := int64(superblock.S_block_start + newBlockIndex*int32(binary.Size(Structs.Folderblock{})))
+			blockOffset := superblock.S_block_start + int64(newBlockIndex)*int64(binary.Size(Structs.Folderblock{}))
 			if err := Utilities.WriteObject(file, newFolderblock, blockOffset); err != nil {
 				return fmt.Errorf("error al escribir el nuevo folderblock: %v", err)
 			}
@@ -699,7 +694,7 @@ func updateParentFolderblock(name string, parentInode int32, newInodeIndex int32
 
 		// Leer el folderblock existente
 		var folderblock Structs.Folderblock
-		blockOffset := int64(superblock.S_block_start + block*int32(binary.Size(Structs.Folderblock{})))
+		blockOffset := superblock.S_block_start + int64(block)*int64(binary.Size(Structs.Folderblock{}))
 		if err := Utilities.ReadObject(file, &folderblock, blockOffset); err != nil {
 			return fmt.Errorf("error al leer el folderblock existente: %v", err)
 		}
@@ -724,7 +719,7 @@ func updateParentFolderblock(name string, parentInode int32, newInodeIndex int32
 
 func handleIndirectBlocks(blockIndex int32, name string, newInodeIndex int32, file *os.File, superblock Structs.Superblock) error {
 	var pointerblock Structs.Pointerblock
-	offset := int64(superblock.S_block_start + blockIndex*int32(binary.Size(Structs.Pointerblock{})))
+	offset := superblock.S_block_start + int64(blockIndex)*int64(binary.Size(Structs.Pointerblock{}))
 	if err := Utilities.ReadObject(file, &pointerblock, offset); err != nil {
 		return err
 	}
@@ -744,13 +739,13 @@ func handleIndirectBlocks(blockIndex int32, name string, newInodeIndex int32, fi
 			copy(newFolderblock.B_content[0].B_name[:], name)
 
 			// Escribir el nuevo folderblock en el archivo
-			offset = int64(superblock.S_block_start + newBlockIndex*int32(binary.Size(Structs.Folderblock{})))
+			offset = superblock.S_block_start + int64(newBlockIndex)*int64(binary.Size(Structs.Folderblock{}))
 			if err := Utilities.WriteObject(file, newFolderblock, offset); err != nil {
 				return err
 			}
 
 			// Escribir el pointerblock actualizado en el archivo
-			offset = int64(superblock.S_block_start + blockIndex*int32(binary.Size(Structs.Pointerblock{})))
+			offset = superblock.S_block_start + int64(blockIndex)*int64(binary.Size(Structs.Pointerblock{}))
 			if err := Utilities.WriteObject(file, pointerblock, offset); err != nil {
 				return err
 			}
@@ -759,7 +754,7 @@ func handleIndirectBlocks(blockIndex int32, name string, newInodeIndex int32, fi
 		}
 
 		var folderblock Structs.Folderblock
-		offset = int64(superblock.S_block_start + pointer*int32(binary.Size(Structs.Folderblock{})))
+		offset = superblock.S_block_start + int64(pointer)*int64(binary.Size(Structs.Folderblock{}))
 		if err := Utilities.ReadObject(file, &folderblock, offset); err != nil {
 			return err
 		}
@@ -796,17 +791,12 @@ func ListDirectories() error {
 	}
 	defer file.Close()
 
-	TempMBR, err := readMBR(file)
+	partition, err := DiskManagement.LocatePartition(file, User.CurrentLoggedPartitionID)
 	if err != nil {
-		return err
-	}
-
-	index := findPartitionIndex(TempMBR, User.CurrentLoggedPartitionID)
-	if index == -1 {
 		return fmt.Errorf("Partición no encontrada (2)")
 	}
 
-	superblock, err := readSuperblock(file, int64(TempMBR.Partitions[index].Start))
+	superblock, err := readSuperblock(file, int64(partition.Start))
 	if err != nil {
 		return err
 	}
@@ -853,7 +843,7 @@ func traverseDirectory(inodeIndex int32, file *os.File, superblock Structs.Super
 
 func readInode(inodeIndex int32, file *os.File, superblock Structs.Superblock) (Structs.Inode, error) {
 	var inode Structs.Inode
-	offset := int64(superblock.S_inode_start + inodeIndex*int32(binary.Size(Structs.Inode{})))
+	offset := superblock.S_inode_start + int64(inodeIndex)*int64(binary.Size(Structs.Inode{}))
 	if err := Utilities.ReadObject(file, &inode, offset); err != nil {
 		return inode, fmt.Errorf("error al leer el inodo: %v", err)
 	}
@@ -862,7 +852,7 @@ func readInode(inodeIndex int32, file *os.File, superblock Structs.Superblock) (
 
 func readFolderBlock(blockIndex int32, file *os.File, superblock Structs.Superblock) (Structs.Folderblock, error) {
 	var folderblock Structs.Folderblock
-	offset := int64(superblock.S_block_start + blockIndex*int32(binary.Size(Structs.Folderblock{})))
+	offset := superblock.S_block_start + int64(blockIndex)*int64(binary.Size(Structs.Folderblock{}))
 	if err := Utilities.ReadObject(file, &folderblock, offset); err != nil {
 		return folderblock, fmt.Errorf("error al leer el folderblock: %v", err)
 	}
@@ -878,7 +868,7 @@ func isDirectory(inode Structs.Inode) bool {
 func findMountedPartition(id string) (DiskManagement.MountedPartition, error) {
 	for _, partitions := range DiskManagement.GetMountedPartitions() {
 		for _, partition := range partitions {
-			if partition.ID == id {
+			if DiskManagement.SameID(partition.ID, id) {
 				if partition.Status != '1' {
 					return partition, fmt.Errorf("La partición aún no está montada")
 				}
@@ -889,26 +879,9 @@ func findMountedPartition(id string) (DiskManagement.MountedPartition, error) {
 	return DiskManagement.MountedPartition{}, fmt.Errorf("Partición no encontrada")
 }
 
-func readMBR(file *os.File) (Structs.MBR, error) {
-	var TempMBR Structs.MBR
-	if err := Utilities.ReadObject(file, &TempMBR, 0); err != nil {
-		return TempMBR, fmt.Errorf("Error al leer MBR del archivo")
-	}
-	return TempMBR, nil
-}
-
-func findPartitionIndex(TempMBR Structs.MBR, id string) int {
-	for i := 0; i < 4; i++ {
-		if TempMBR.Partitions[i].Size != 0 && strings.Contains(string(TempMBR.Partitions[i].Id[:]), id) {
-			return i
-		}
-	}
-	return -1
-}
-
 func readSuperblock(file *os.File, start int64) (Structs.Superblock, error) {
-	var superblock Structs.Superblock
-	if err := Utilities.ReadObject(file, &superblock, start); err != nil {
+	superblock, err := DiskManagement.ReadSuperblock(file, start)
+	if err != nil {
 		return superblock, fmt.Errorf("Error al leer el superbloque")
 	}
 	return superblock, nil
@@ -921,85 +894,14 @@ type MKFILE struct {
 	cont string // Contenido del archivo
 }
 
-// ParserMkfile parsea el comando mkfile y devuelve una instancia de MKFILE
-func ParserMkfile(tokens []string) (string, error) {
-	cmd := &MKFILE{} // Crea una nueva instancia de MKFILE
-
-	// Unir tokens en una sola cadena y luego dividir por espacios, respetando las comillas
-	args := strings.Join(tokens, " ")
-	// Expresión regular para encontrar los parámetros del comando mkfile
-	re := regexp.MustCompile(`-path="[^"]+"|-path=[^\s]+|-r|-size=\d+|-cont="[^"]+"|-cont=[^\s]+`)
-	// Encuentra todas las coincidencias de la expresión regular en la cadena de argumentos
-	matches := re.FindAllString(args, -1)
-
-	// Verificar que todos los tokens fueron reconocidos por la expresión regular
-	if len(matches) != len(tokens) {
-		// Identificar el parámetro inválido
-		for _, token := range tokens {
-			if !re.MatchString(token) {
-				return "", fmt.Errorf("parámetro inválido: %s", token)
-			}
-		}
-	}
-
-	// Itera sobre cada coincidencia encontrada
-	for _, match := range matches {
-		// Divide cada parte en clave y valor usando "=" como delimitador
-		kv := strings.SplitN(match, "=", 2)
-		key := strings.ToLower(kv[0])
-		var value string
-		if len(kv) == 2 {
-			value = kv[1]
-		}
-
-		// Remove quotes from value if present
-		if strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"") {
-			value = strings.Trim(value, "\"")
-		}
-
-		// Switch para manejar diferentes parámetros
-		switch key {
-		case "-path":
-			// Verifica que el path no esté vacío
-			if value == "" {
-				return "", errors.New("el path no puede estar vacío")
-			}
-			cmd.path = value
-		case "-r":
-			// Establece el valor de r a true
-			cmd.r = true
-		case "-size":
-			// Convierte el valor del tamaño a un entero
-			size, err := strconv.Atoi(value)
-			if err != nil || size < 0 {
-				return "", errors.New("el tamaño debe ser un número entero no negativo")
-			}
-			cmd.size = size
-		case "-cont":
-			// Verifica que el contenido no esté vacío
-			if value == "" {
-				return "", errors.New("el contenido no puede estar vacío")
-			}
-			cmd.cont = value
-		default:
-			// Si el parámetro no es reconocido, devuelve un error
-			return "", fmt.Errorf("parámetro desconocido: %s", key)
-		}
-	}
-
-	// Verifica que el parámetro -path haya sido proporcionado
-	if cmd.path == "" {
-		return "", errors.New("faltan parámetros requeridos: -path")
-	}
-
-	// Si no se proporcionó el tamaño, se establece por defecto a 0
-	if cmd.size == 0 {
-		cmd.size = 0
-	}
-
-	// Si no se proporcionó el contenido, se establece por defecto a ""
-	if cmd.cont == "" {
-		cmd.cont = ""
+// ParserMkfile crea el archivo con los parámetros del comando mkfile, que ya se validaron contra su
+// definición en el registro de comandos
+func ParserMkfile(command Structs.Command) (string, error) {
+	cmd := &MKFILE{
+		path: command.Value("path"),
+		r:    command.Has("r"),
+		size: command.Int("size"),
+		cont: command.Value("cont"),
 	}
 
 	// Crear el archivo con los parámetros proporcionados
@@ -1008,7 +910,7 @@ func ParserMkfile(tokens []string) (string, error) {
 		return "", err
 	}
 
-	return fmt.Sprintf("MKFILE: Archivo %s creado correctamente.", cmd.path), nil // Devuelve el comando MKFILE creado
+	return fmt.Sprintf("MKFILE: Archivo %s creado correctamente.", cmd.path), nil
 }
 
 // Función para crear el archivo
@@ -1050,7 +952,7 @@ func getMountedPartition() (*DiskManagement.MountedPartition, error) {
 
 	for _, partitions := range DiskManagement.GetMountedPartitions() {
 		for _, partition := range partitions {
-			if partition.ID == User.CurrentLoggedPartitionID {
+			if DiskManagement.SameID(partition.ID, User.CurrentLoggedPartitionID) {
 				if partition.Status != '1' {
 					return nil, errors.New("la partición aún no está montada")
 				}
@@ -1071,28 +973,16 @@ func createFile(filePath string, size int, content string, partition *DiskManage
 	}
 	defer file.Close()
 
-	// Leer el MBR para obtener el inicio de la partición
-	var mbr Structs.MBR
-	if err := Utilities.ReadObject(file, &mbr, 0); err != nil {
-		return fmt.Errorf("error al leer el MBR: %v", err)
-	}
-
-	// Encontrar la partición montada
-	var partitionStart int64
-	for _, part := range mbr.Partitions {
-		if strings.TrimSpace(string(part.Id[:])) == partition.ID {
-			partitionStart = int64(part.Start)
-			break
-		}
-	}
-
-	if partitionStart == 0 {
+	// Encontrar la partición montada para obtener su inicio
+	located, err := DiskManagement.LocatePartition(file, partition.ID)
+	if err != nil {
 		return fmt.Errorf("no se encontró la partición montada")
 	}
+	partitionStart := int64(located.Start)
 
 	// Leer el superbloque
-	var superblock Structs.Superblock
-	if err := Utilities.ReadObject(file, &superblock, partitionStart); err != nil {
+	superblock, err := DiskManagement.ReadSuperblock(file, partitionStart)
+	if err != nil {
 		return fmt.Errorf("error al leer el superbloque: %v", err)
 	}
 
@@ -1136,34 +1026,15 @@ func getParentDirectories(filePath string) ([]string, string) {
 
 // Función para crear un archivo en un directorio
 func createFileInDirectory(fileName string, parentInode int32, size int, content string, file *os.File, superblock Structs.Superblock, mountedPartition DiskManagement.MountedPartition) error {
-	// Leer el MBR para obtener el inicio de la partición
-	var mbr Structs.MBR
-	if err := Utilities.ReadObject(file, &mbr, 0); err != nil {
-		return fmt.Errorf("error al leer el MBR: %v", err)
-	}
-
 	// Encontrar la partición montada y obtener su inicio
-	var partitionStart int64
-	var partitionIndex int = -1
-	for i := 0; i < 4; i++ {
-		if mbr.Partitions[i].Size != 0 && strings.Contains(string(mbr.Partitions[i].Id[:]), mountedPartition.ID) {
-			partitionStart = int64(mbr.Partitions[i].Start)
-			partitionIndex = i
-			break
-		}
-	}
-
-	if partitionStart == 0 {
+	located, err := DiskManagement.LocatePartition(file, mountedPartition.ID)
+	if err != nil {
 		return fmt.Errorf("no se encontró la partición montada")
 	}
-
-	// Obtener la partición y verificar que haya sido encontrada
-	if partitionIndex == -1 {
-		return fmt.Errorf("no se encontró la partición correspondiente")
-	}
+	partitionStart := int64(located.Start)
 
 	// Actualiza el superbloque si hay cambios en los inodos o bloques
-	if err := Utilities.WriteObject(file, superblock, partitionStart); err != nil {
+	if err := DiskManagement.WriteSuperblock(file, superblock, partitionStart); err != nil {
 		return fmt.Errorf("error al actualizar el superbloque: %v", err)
 	}
 
@@ -1187,7 +1058,7 @@ func createFileInDirectory(fileName string, parentInode int32, size int, content
 	newInode.I_block[0] = newBlockIndex
 
 	// Escribe el nuevo inodo en el offset correcto para el nuevo inodo
-	inodeOffset := int64(superblock.S_inode_start + newInodeIndex*int32(binary.Size(Structs.Inode{})))
+	inodeOffset := superblock.S_inode_start + int64(newInodeIndex)*int64(binary.Size(Structs.Inode{}))
 	if err := Utilities.WriteObject(file, newInode, inodeOffset); err != nil {
 		return fmt.Errorf("error al escribir el inodo del archivo: %v", err)
 	}
@@ -1200,7 +1071,7 @@ func createFileInDirectory(fileName string, parentInode int32, size int, content
 	fmt.Printf("Escribiendo contenido en el bloque: %s\n", content)
 
 	// Escribe el nuevo fileblock en el archivo
-	blockOffset := int64(superblock.S_block_start + newBlockIndex*int32(binary.Size(Structs.Fileblock{})))
+	blockOffset := superblock.S_block_start + int64(newBlockIndex)*int64(binary.Size(Structs.Fileblock{}))
 	if err := Utilities.WriteObject(file, newFileblock, blockOffset); err != nil {
 		return fmt.Errorf("error al escribir el bloque de archivo: %v", err)
 	}
@@ -1218,7 +1089,7 @@ func createFileInDirectory(fileName string, parentInode int32, size int, content
 	}
 
 	// Escribir de nuevo el superbloque con las modificaciones
-	if err := Utilities.WriteObject(file, superblock, partitionStart); err != nil {
+	if err := DiskManagement.WriteSuperblock(file, superblock, partitionStart); err != nil {
 		return fmt.Errorf("error al actualizar el superbloque final: %v", err)
 	}
 
@@ -1227,7 +1098,7 @@ func createFileInDirectory(fileName string, parentInode int32, size int, content
 
 // Función auxiliar para marcar un inodo como usado
 func markInodeAsUsed(inodeIndex int32, superblock Structs.Superblock, file *os.File) error {
-	bitmapOffset := int64(superblock.S_bm_inode_start + inodeIndex)
+	bitmapOffset := superblock.S_bm_inode_start + int64(inodeIndex)
 	if err := Utilities.WriteObject(file, byte(1), bitmapOffset); err != nil {
 		return fmt.Errorf("error al marcar el inodo en el bitmap: %v", err)
 	}
@@ -1237,7 +1108,7 @@ func markInodeAsUsed(inodeIndex int32, superblock Structs.Superblock, file *os.F
 
 // Función auxiliar para marcar un bloque como usado
 func markBlockAsUsed(blockIndex int32, superblock Structs.Superblock, file *os.File) error {
-	bitmapOffset := int64(superblock.S_bm_block_start + blockIndex)
+	bitmapOffset := superblock.S_bm_block_start + int64(blockIndex)
 	if err := Utilities.WriteObject(file, byte(1), bitmapOffset); err != nil {
 		return fmt.Errorf("error al marcar el bloque en el bitmap: %v", err)
 	}
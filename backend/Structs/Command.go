@@ -0,0 +1,54 @@
+package Structs
+
+import (
+	"strconv"
+)
+
+// Command representa una línea ya tokenizada: el nombre del comando y sus parámetros en orden
+type Command struct {
+	Name   string      // Nombre del comando en minúsculas (mkdisk, fdisk, ...)
+	Column int         // Columna (1-based) donde inicia el nombre del comando
+	Params []Parameter // Parámetros en el orden en que aparecen en la línea
+	Args   []Parameter // Argumentos posicionales (sin guion), por ejemplo "help mkdisk"
+	Raw    string      // Texto original de la línea, sin el comentario final
+}
+
+// Parameter representa un parámetro -nombre=valor (o una bandera -nombre sin valor).
+// En los argumentos posicionales Name queda vacío.
+type Parameter struct {
+	Name     string // Nombre del parámetro en minúsculas, sin el guion
+	Value    string // Valor ya sin comillas ni escapes
+	Column   int    // Columna (1-based) donde inicia el parámetro (el guion)
+	HasValue bool   // true si el parámetro tenía "=", aunque el valor esté vacío
+}
+
+// Get devuelve el valor del parámetro; si se repite, gana la última aparición
+func (c Command) Get(name string) (string, bool) {
+	for i := len(c.Params) - 1; i >= 0; i-- {
+		if c.Params[i].Name == name {
+			return c.Params[i].Value, true
+		}
+	}
+	return "", false
+}
+
+// Value devuelve el valor del parámetro o "" si no existe
+func (c Command) Value(name string) string {
+	value, _ := c.Get(name)
+	return value
+}
+
+// Has indica si el parámetro aparece en la línea
+func (c Command) Has(name string) bool {
+	_, ok := c.Get(name)
+	return ok
+}
+
+// Int devuelve el valor del parámetro como entero, o 0 si no es numérico
+func (c Command) Int(name string) int {
+	n, err := strconv.Atoi(c.Value(name))
+	if err != nil {
+		return 0
+	}
+	return n
+}
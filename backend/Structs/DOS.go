@@ -0,0 +1,115 @@
+package Structs
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// MBR estándar de DOS: el sector 0 de 512 bytes que leen fdisk, sfdisk y parted. Las posiciones y
+// los tamaños de sus entradas son sectores (LBA) y además van en CHS para las herramientas viejas.
+// Las particiones lógicas se enlazan con EBR del mismo formato: la primera entrada es la lógica,
+// relativa a su EBR, y la segunda apunta al siguiente EBR, relativa al inicio de la extendida.
+
+// DOSSectorSize es el tamaño de sector con el que se exportan e importan las imágenes
+const DOSSectorSize = 512
+
+// DOSBootSignature cierra el MBR y cada EBR
+var DOSBootSignature = [2]byte{0x55, 0xAA}
+
+// Tipos de partición que se usan al exportar
+const (
+	DOSTypeEmpty    byte = 0x00
+	DOSTypeExtended byte = 0x05 // Extendida con direcciones CHS
+	DOSTypeLinux    byte = 0x83 // Primarias y lógicas
+	DOSTypeExtLBA   byte = 0x0F // Extendida solo LBA (otras herramientas)
+	DOSTypeExtLinux byte = 0x85 // Extendida de Linux
+	DOSTypeGPT      byte = 0xEE // MBR protector de un disco GPT real
+)
+
+type DOSPartitionEntry struct {
+	Status   byte    // 0x80 si es la partición de arranque
+	CHSFirst [3]byte // Primer sector en CHS
+	Type     byte    // Código de tipo
+	CHSLast  [3]byte // Último sector en CHS
+	LBAStart uint32  // Primer sector
+	Sectors  uint32  // Cantidad de sectores
+}
+
+type DOSMBR struct {
+	Boot          [440]byte // Código de arranque; al exportar guarda DOSBootInfo
+	DiskSignature uint32
+	Reserved      uint16
+	Entries       [4]DOSPartitionEntry
+	BootSignature [2]byte // DOSBootSignature
+}
+
+// IsExtended indica si el tipo de la entrada es el de una partición extendida
+func (e DOSPartitionEntry) IsExtended() bool {
+	return e.Type == DOSTypeExtended || e.Type == DOSTypeExtLBA || e.Type == DOSTypeExtLinux
+}
+
+// Used indica si la entrada tiene una partición
+func (e DOSPartitionEntry) Used() bool {
+	return e.Type != DOSTypeEmpty && e.Sectors > 0
+}
+
+// DOSBootInfoMagic identifica un DOSBootInfo en el área de arranque
+var DOSBootInfoMagic = [4]byte{'M', 'I', 'A', '1'}
+
+// DOSBootMaxPartitions es cuántas particiones describe DOSBootInfo: las 4 primarias y 12 lógicas
+const DOSBootMaxPartitions = 16
+
+// DOSBootInfo guarda en el área de arranque del MBR exportado lo que la tabla DOS no tiene, para
+// que al importar la imagen el disco quede igual. Las particiones van numeradas como en Linux y en
+// los IDs de montaje: 1 a 4 las entradas del MBR y de 5 en adelante las lógicas en orden.
+type DOSBootInfo struct {
+	Magic        [4]byte  // DOSBootInfoMagic
+	Format       int32    // Formato del disco original
+	Fit          [1]byte  // Ajuste del disco
+	CreationDate [10]byte // Fecha de creación del disco
+	Names        [DOSBootMaxPartitions][16]byte
+	Fits         [DOSBootMaxPartitions]byte
+	Sizes        [DOSBootMaxPartitions]int64 // Tamaño exacto en bytes; en la tabla se redondea a sectores
+}
+
+// SetBootInfo guarda info en el área de arranque
+func (m *DOSMBR) SetBootInfo(info DOSBootInfo) {
+	var buffer bytes.Buffer
+	binary.Write(&buffer, binary.LittleEndian, info)
+	m.Boot = [440]byte{}
+	copy(m.Boot[:], buffer.Bytes())
+}
+
+// BootInfo lee el DOSBootInfo del área de arranque; false si la imagen no lo tiene
+func (m DOSMBR) BootInfo() (DOSBootInfo, bool) {
+	var info DOSBootInfo
+	if err := binary.Read(bytes.NewReader(m.Boot[:]), binary.LittleEndian, &info); err != nil {
+		return info, false
+	}
+	return info, info.Magic == DOSBootInfoMagic
+}
+
+// DOSCHS convierte un sector LBA a CHS con la geometría habitual de 255 cabezas y 63 sectores por
+// pista; los sectores más allá del cilindro 1023 se marcan con el máximo, como hacen fdisk y sfdisk
+func DOSCHS(lba uint32) [3]byte {
+	const heads, sectors = 255, 63
+	cylinder := lba / (heads * sectors)
+	if cylinder > 1023 {
+		return [3]byte{0xFE, 0xFF, 0xFF}
+	}
+	head := (lba / sectors) % heads
+	sector := lba%sectors + 1
+	return [3]byte{byte(head), byte(sector) | byte((cylinder>>2)&0xC0), byte(cylinder)}
+}
+
+// NewDOSEntry arma una entrada con sus direcciones LBA y CHS. lbaStart es relativo a base (el EBR o
+// el inicio de la extendida en las entradas de un EBR, 0 en el MBR); el CHS siempre es absoluto.
+func NewDOSEntry(type_ byte, base uint32, lbaStart uint32, sectors uint32) DOSPartitionEntry {
+	return DOSPartitionEntry{
+		CHSFirst: DOSCHS(base + lbaStart),
+		Type:     type_,
+		CHSLast:  DOSCHS(base + lbaStart + sectors - 1),
+		LBAStart: lbaStart,
+		Sectors:  sectors,
+	}
+}
@@ -0,0 +1,181 @@
+package Structs
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+)
+
+// Estructuras del formato de 32 bits, tal como están en los discos creados con él.
+// En memoria siempre se trabaja con MBR, EBR y Superblock; estas solo se usan al leer y escribir.
+
+type MBR32 struct {
+	MbrSize      int32
+	CreationDate [10]byte
+	Signature    int32
+	Fit          [1]byte
+	Partitions   [4]Partition32
+}
+
+type Partition32 struct {
+	Status      [1]byte
+	Type        [1]byte
+	Fit         [1]byte
+	Start       int32
+	Size        int32
+	Name        [16]byte
+	Correlative int32
+	Id          [4]byte
+}
+
+type EBR32 struct {
+	PartMount byte
+	PartFit   byte
+	PartStart int32
+	PartSize  int32
+	PartNext  int32
+	PartName  [16]byte
+}
+
+type Superblock32 struct {
+	S_filesystem_type   int32
+	S_inodes_count      int32
+	S_blocks_count      int32
+	S_free_blocks_count int32
+	S_free_inodes_count int32
+	S_mtime             [17]byte
+	S_umtime            [17]byte
+	S_mnt_count         int32
+	S_magic             int32
+	S_inode_size        int32
+	S_block_size        int32
+	S_fist_ino          int32
+	S_first_blo         int32
+	S_bm_inode_start    int32
+	S_bm_block_start    int32
+	S_inode_start       int32
+	S_block_start       int32
+}
+
+// to32 convierte un tamaño o posición al formato de 32 bits; falla en vez de desbordarse
+func to32(field string, value int64) (int32, error) {
+	if value < math.MinInt32 || value > math.MaxInt32 {
+		return 0, fmt.Errorf("%s = %d no cabe en el formato de 32 bits", field, value)
+	}
+	return int32(value), nil
+}
+
+// Expand convierte el MBR leído de un disco de 32 bits al MBR en memoria
+func (m MBR32) Expand() MBR {
+	mbr := MBR{Version: Format32, MbrSize: int64(m.MbrSize), CreationDate: m.CreationDate, Signature: m.Signature, Fit: m.Fit}
+	for i, p := range m.Partitions {
+		mbr.Partitions[i] = Partition{Status: p.Status, Type: p.Type, Fit: p.Fit, Start: int64(p.Start), Size: int64(p.Size), Name: p.Name, Correlative: p.Correlative}
+		copy(mbr.Partitions[i].Id[:], p.Id[:])
+	}
+	return mbr
+}
+
+// ShrinkMBR convierte el MBR en memoria al del formato de 32 bits
+func ShrinkMBR(mbr MBR) (MBR32, error) {
+	m := MBR32{CreationDate: mbr.CreationDate, Signature: mbr.Signature, Fit: mbr.Fit}
+	var err error
+	if m.MbrSize, err = to32("MbrSize", mbr.MbrSize); err != nil {
+		return m, err
+	}
+	for i, p := range mbr.Partitions {
+		start, err := to32("Start", p.Start)
+		if err != nil {
+			return m, err
+		}
+		size, err := to32("Size", p.Size)
+		if err != nil {
+			return m, err
+		}
+		m.Partitions[i] = Partition32{Status: p.Status, Type: p.Type, Fit: p.Fit, Start: start, Size: size, Name: p.Name, Correlative: p.Correlative}
+		if id := bytes.TrimRight(p.Id[:], "\x00"); len(id) > len(m.Partitions[i].Id) {
+			return m, fmt.Errorf("el ID %s no cabe en los %d bytes del formato de 32 bits", id, len(m.Partitions[i].Id))
+		}
+		copy(m.Partitions[i].Id[:], p.Id[:])
+	}
+	return m, nil
+}
+
+// Expand convierte el EBR leído de un disco de 32 bits al EBR en memoria
+func (e EBR32) Expand() EBR {
+	return EBR{PartMount: e.PartMount, PartFit: e.PartFit, PartStart: int64(e.PartStart), PartSize: int64(e.PartSize), PartNext: int64(e.PartNext), PartName: e.PartName}
+}
+
+// ShrinkEBR convierte el EBR en memoria al del formato de 32 bits, que no tiene campo para el ID
+func ShrinkEBR(ebr EBR) (EBR32, error) {
+	e := EBR32{PartMount: ebr.PartMount, PartFit: ebr.PartFit, PartName: ebr.PartName}
+	if id := bytes.TrimRight(ebr.PartId[:], "\x00"); len(id) > 0 {
+		return e, fmt.Errorf("el ID %s de la partición lógica no se puede guardar en el formato de 32 bits", id)
+	}
+	var err error
+	if e.PartStart, err = to32("PartStart", ebr.PartStart); err != nil {
+		return e, err
+	}
+	if e.PartSize, err = to32("PartSize", ebr.PartSize); err != nil {
+		return e, err
+	}
+	if e.PartNext, err = to32("PartNext", ebr.PartNext); err != nil {
+		return e, err
+	}
+	return e, nil
+}
+
+// Expand convierte el superbloque leído de un disco de 32 bits al superbloque en memoria
+func (s Superblock32) Expand() Superblock {
+	return Superblock{
+		S_filesystem_type:   s.S_filesystem_type,
+		S_inodes_count:      s.S_inodes_count,
+		S_blocks_count:      s.S_blocks_count,
+		S_free_blocks_count: s.S_free_blocks_count,
+		S_free_inodes_count: s.S_free_inodes_count,
+		S_mtime:             s.S_mtime,
+		S_umtime:            s.S_umtime,
+		S_mnt_count:         s.S_mnt_count,
+		S_magic:             s.S_magic,
+		S_inode_size:        s.S_inode_size,
+		S_block_size:        s.S_block_size,
+		S_fist_ino:          s.S_fist_ino,
+		S_first_blo:         s.S_first_blo,
+		S_bm_inode_start:    int64(s.S_bm_inode_start),
+		S_bm_block_start:    int64(s.S_bm_block_start),
+		S_inode_start:       int64(s.S_inode_start),
+		S_block_start:       int64(s.S_block_start),
+	}
+}
+
+// ShrinkSuperblock convierte el superbloque en memoria al del formato de 32 bits
+func ShrinkSuperblock(sb Superblock) (Superblock32, error) {
+	s := Superblock32{
+		S_filesystem_type:   sb.S_filesystem_type,
+		S_inodes_count:      sb.S_inodes_count,
+		S_blocks_count:      sb.S_blocks_count,
+		S_free_blocks_count: sb.S_free_blocks_count,
+		S_free_inodes_count: sb.S_free_inodes_count,
+		S_mtime:             sb.S_mtime,
+		S_umtime:            sb.S_umtime,
+		S_mnt_count:         sb.S_mnt_count,
+		S_magic:             sb.S_magic,
+		S_inode_size:        sb.S_inode_size,
+		S_block_size:        sb.S_block_size,
+		S_fist_ino:          sb.S_fist_ino,
+		S_first_blo:         sb.S_first_blo,
+	}
+	var err error
+	if s.S_bm_inode_start, err = to32("S_bm_inode_start", sb.S_bm_inode_start); err != nil {
+		return s, err
+	}
+	if s.S_bm_block_start, err = to32("S_bm_block_start", sb.S_bm_block_start); err != nil {
+		return s, err
+	}
+	if s.S_inode_start, err = to32("S_inode_start", sb.S_inode_start); err != nil {
+		return s, err
+	}
+	if s.S_block_start, err = to32("S_block_start", sb.S_block_start); err != nil {
+		return s, err
+	}
+	return s, nil
+}
@@ -0,0 +1,95 @@
+package Structs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+)
+
+// Tabla de particiones tipo GPT. El disco empieza con el encabezado principal seguido del arreglo
+// de entradas; al final del disco están la copia del arreglo y, en los últimos bytes, el encabezado
+// de respaldo. Los dos encabezados y los dos arreglos llevan CRC32 para detectar si se dañaron.
+// Un disco GPT siempre usa el formato de 64 bits.
+
+// GPTTag ocupa los primeros 4 bytes de un disco GPT, igual que FormatTag en uno de 64 bits con MBR
+const GPTTag int32 = -0x4750
+
+// GPTSignature identifica los encabezados GPT
+var GPTSignature = [8]byte{'M', 'I', 'A', ' ', 'P', 'A', 'R', 'T'}
+
+// GPTPartitionType es el GUID de tipo de las entradas usadas; una entrada libre lo tiene en ceros
+var GPTPartitionType = [16]byte{0xAF, 0x3D, 0xC6, 0x0F, 0x83, 0x84, 0x72, 0x47, 0x8E, 0x79, 0x3D, 0x69, 0xD8, 0x47, 0x7D, 0xE4}
+
+type GPTHeader struct {
+	Tag           int32    // GPTTag
+	Version       int32    // Siempre Format64
+	Signature     [8]byte  // GPTSignature
+	HeaderCRC     uint32   // CRC32 del encabezado calculado con este campo en cero
+	MyPosition    int64    // Posición de este encabezado
+	AltPosition   int64    // Posición del otro encabezado
+	FirstUsable   int64    // Primer byte disponible para particiones
+	LastUsable    int64    // Último byte disponible para particiones
+	DiskGUID      [16]byte // GUID del disco
+	EntriesStart  int64    // Posición del arreglo de entradas que corresponde a este encabezado
+	EntryCount    int32    // Cantidad de entradas del arreglo
+	EntrySize     int32    // Tamaño de cada entrada
+	EntriesCRC    uint32   // CRC32 del arreglo de entradas
+	DiskSize      int64    // Tamaño del disco en bytes
+	CreationDate  [10]byte // Fecha de creación, igual que en el MBR
+	DiskSignature int32    // Firma del disco, igual que en el MBR
+	Fit           [1]byte  // Ajuste del disco
+}
+
+type GPTEntry struct {
+	TypeGUID    [16]byte // GPTPartitionType o ceros si la entrada está libre
+	PartGUID    [16]byte // GUID único de la partición
+	Start       int64
+	Size        int64
+	Status      [1]byte
+	Fit         [1]byte
+	Name        [16]byte
+	Correlative int32
+	Id          [8]byte
+}
+
+// ChecksumGPT calcula el CRC32 de la estructura o del arreglo tal como se escribe en el disco
+func ChecksumGPT(data interface{}) uint32 {
+	var buffer bytes.Buffer
+	binary.Write(&buffer, binary.LittleEndian, data)
+	return crc32.ChecksumIEEE(buffer.Bytes())
+}
+
+// HeaderChecksum calcula el CRC32 del encabezado con HeaderCRC en cero
+func (h GPTHeader) HeaderChecksum() uint32 {
+	h.HeaderCRC = 0
+	return ChecksumGPT(h)
+}
+
+// Used indica si la entrada tiene una partición
+func (e GPTEntry) Used() bool {
+	return e.TypeGUID != [16]byte{} && e.Size > 0
+}
+
+// Partition convierte la entrada a la Partition en memoria; las particiones GPT siempre son primarias
+func (e GPTEntry) Partition() Partition {
+	if !e.Used() {
+		return Partition{}
+	}
+	partition := Partition{Status: e.Status, Fit: e.Fit, Start: e.Start, Size: e.Size, Name: e.Name, Correlative: e.Correlative, Id: e.Id}
+	partition.Type[0] = 'p'
+	return partition
+}
+
+// NewGPTEntry arma la entrada de una partición con su GUID; una partición vacía da una entrada libre
+func NewGPTEntry(partition Partition, guid [16]byte) GPTEntry {
+	if partition.Size == 0 {
+		return GPTEntry{}
+	}
+	return GPTEntry{TypeGUID: GPTPartitionType, PartGUID: guid, Start: partition.Start, Size: partition.Size, Status: partition.Status, Fit: partition.Fit, Name: partition.Name, Correlative: partition.Correlative, Id: partition.Id}
+}
+
+func PrintGPTHeader(data GPTHeader) {
+	fmt.Println(fmt.Sprintf("CreationDate: %s, fit: %s, size: %d, entries: %d, usable: %d-%d, position: %d, backup: %d",
+		string(data.CreationDate[:]), string(data.Fit[:]), data.DiskSize, data.EntryCount, data.FirstUsable, data.LastUsable, data.MyPosition, data.AltPosition))
+}
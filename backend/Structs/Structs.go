@@ -4,8 +4,22 @@ import (
 	"fmt"
 )
 
+// Formatos del disco. El de 32 bits es el original (MBR32, EBR32 y Superblock32); el de 64 bits
+// guarda tamaños y posiciones en int64 y empieza con FormatTag para distinguirlo.
+const (
+	Format32 int32 = 1
+	Format64 int32 = 2
+)
+
+// FormatTag ocupa los primeros 4 bytes de un disco de 64 bits. En el formato de 32 bits ahí está
+// el tamaño del disco, que nunca es negativo, así que el valor no se confunde.
+const FormatTag int32 = -0x4D49
+
+// MBR es el MBR en memoria y, tal cual, el del formato de 64 bits
 type MBR struct {
-	MbrSize      int32    // 4 bytes //int32 va desde -2,147,483,648 hasta 2,147,483,647.
+	Tag          int32    // FormatTag en disco; en memoria puede ser 0
+	Version      int32    // Format32 o Format64: formato en que se lee y escribe el disco
+	MbrSize      int64    // 8 bytes
 	CreationDate [10]byte // 10 bytes
 	Signature    int32    // 4 bytes
 	Fit          [1]byte  // 1 byte
@@ -13,7 +27,7 @@ type MBR struct {
 }
 
 func PrintMBR(data MBR) {
-	fmt.Println(fmt.Sprintf("CreationDate: %s, fit: %s, size: %d", string(data.CreationDate[:]), string(data.Fit[:]), data.MbrSize))
+	fmt.Println(fmt.Sprintf("CreationDate: %s, fit: %s, size: %d, format: %d", string(data.CreationDate[:]), string(data.Fit[:]), data.MbrSize, data.Version))
 	for i := 0; i < 4; i++ {
 		PrintPartition(data.Partitions[i])
 	}
@@ -23,11 +37,11 @@ type Partition struct {
 	Status      [1]byte
 	Type        [1]byte
 	Fit         [1]byte
-	Start       int32
-	Size        int32
+	Start       int64
+	Size        int64
 	Name        [16]byte
 	Correlative int32
-	Id          [4]byte
+	Id          [8]byte // En el formato de 32 bits se guardan solo 4 bytes
 }
 
 func PrintPartition(data Partition) {
@@ -37,20 +51,22 @@ func PrintPartition(data Partition) {
 type EBR struct {
 	PartMount byte
 	PartFit   byte
-	PartStart int32
-	PartSize  int32
-	PartNext  int32
+	PartStart int64
+	PartSize  int64
+	PartNext  int64
 	PartName  [16]byte
+	PartId    [8]byte // ID de montaje de la lógica; el EBR32 del formato de 32 bits no lo tiene
 }
 
 func PrintEBR(data EBR) {
-	fmt.Println(fmt.Sprintf("Name: %s, fit: %c, start: %d, size: %d, next: %d, mount: %c",
+	fmt.Println(fmt.Sprintf("Name: %s, fit: %c, start: %d, size: %d, next: %d, mount: %c, id: %s",
 		string(data.PartName[:]),
 		data.PartFit,
 		data.PartStart,
 		data.PartSize,
 		data.PartNext,
-		data.PartMount))
+		data.PartMount,
+		string(data.PartId[:])))
 }
 
 //Estructuras relacionadas a EXT2
@@ -69,10 +85,10 @@ type Superblock struct {
 	S_block_size        int32    // Tamaño del bloque
 	S_fist_ino          int32    // Primer inodo libre (dirección del inodo)
 	S_first_blo         int32    // Primer bloque libre (dirección del inodo)
-	S_bm_inode_start    int32    // Guardará el inicio del bitmap de inodos
-	S_bm_block_start    int32    // Guardará el inicio del bitmap de bloques
-	S_inode_start       int32    // Guardará el inicio de la tabla de inodos
-	S_block_start       int32    // Guardará el inicio de la tabla de bloques
+	S_bm_inode_start    int64    // Guardará el inicio del bitmap de inodos
+	S_bm_block_start    int64    // Guardará el inicio del bitmap de bloques
+	S_inode_start       int64    // Guardará el inicio de la tabla de inodos
+	S_block_start       int64    // Guardará el inicio de la tabla de bloques
 }
 
 func PrintSuperblock(sb Superblock) {
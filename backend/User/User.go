@@ -11,6 +11,7 @@ import (
 )
 
 var CurrentLoggedPartitionID string // ID de la partición logueada actualmente
+var CurrentUser string              // Nombre del usuario con la sesión activa
 
 func Login(user string, pass string, id string) (string, error) {
 	fmt.Println("======Start LOGIN======")
@@ -26,11 +27,11 @@ func Login(user string, pass string, id string) (string, error) {
 
 	for _, partitions := range mountedPartitions {
 		for _, partition := range partitions {
-			if partition.ID == id && partition.LoggedIn { // Verifica si ya está logueado
+			if DiskManagement.SameID(partition.ID, id) && partition.LoggedIn { // Verifica si ya está logueado
 				fmt.Println("Ya existe un usuario logueado!")
-				return "Ya existe un usuario logueado!", nil
+				return "", fmt.Errorf("ya existe un usuario logueado en la partición %s", id)
 			}
-			if partition.ID == id { // Encuentra la partición correcta
+			if DiskManagement.SameID(partition.ID, id) { // Encuentra la partición correcta
 				filepath = partition.Path
 				partitionFound = true
 				break
@@ -43,58 +44,51 @@ func Login(user string, pass string, id string) (string, error) {
 
 	if !partitionFound {
 		fmt.Println("Error: No se encontró ninguna partición montada con el ID proporcionado")
-		return "Error: No se encontró ninguna partición montada con el ID proporcionado", nil
+		return "", fmt.Errorf("no se encontró ninguna partición montada con el ID %s", id)
 	}
 
 	// Abrir archivo binario
 	file, err := Utilities.OpenFile(filepath)
 	if err != nil {
 		fmt.Println("Error: No se pudo abrir el archivo:", err)
-		return "Error: No se pudo abrir el archivo", err
+		return "", fmt.Errorf("no se pudo abrir el archivo: %v", err)
 	}
 	defer file.Close()
 
-	var TempMBR Structs.MBR
-	// Leer el MBR desde el archivo binario
-	if err := Utilities.ReadObject(file, &TempMBR, 0); err != nil {
+	// Leer la tabla de particiones (MBR o GPT) desde el archivo binario
+	table, err := DiskManagement.ReadTable(file)
+	if err != nil {
 		fmt.Println("Error: No se pudo leer el MBR:", err)
-		return "Error: No se pudo leer el MBR", err
+		return "", fmt.Errorf("no se pudo leer el MBR: %v", err)
 	}
 
-	// Imprimir el MBR
-	Structs.PrintMBR(TempMBR)
-	fmt.Println("-------------")
-
-	var index int = -1
-	// Iterar sobre las particiones del MBR para encontrar la correcta
-	for i := 0; i < 4; i++ {
-		if TempMBR.Partitions[i].Size != 0 {
-			if strings.Contains(string(TempMBR.Partitions[i].Id[:]), id) {
-				fmt.Println("Partition found")
-				if TempMBR.Partitions[i].Status[0] == '1' {
-					fmt.Println("Partition is mounted")
-					index = i
-				} else {
-					fmt.Println("Partition is not mounted")
-					return "Error: La partición no está montada", nil
-				}
-				break
-			}
-		}
+	// Imprimir el MBR o el encabezado de la GPT
+	if table.GPT != nil {
+		Structs.PrintGPTHeader(*table.GPT)
+	} else {
+		Structs.PrintMBR(table.MBR)
 	}
+	fmt.Println("-------------")
 
-	if index != -1 {
-		Structs.PrintPartition(TempMBR.Partitions[index])
-	} else {
+	// Buscar la partición (primaria o lógica) con el ID indicado
+	partition, err := DiskManagement.LocatePartition(file, id)
+	if err != nil {
 		fmt.Println("Partition not found")
-		return "Error: La partición no fue encontrada", nil
+		return "", fmt.Errorf("la partición %s no fue encontrada", id)
+	}
+	fmt.Println("Partition found")
+	if partition.Status[0] != '1' {
+		fmt.Println("Partition is not mounted")
+		return "", fmt.Errorf("la partición %s no está montada", id)
 	}
+	fmt.Println("Partition is mounted")
+	Structs.PrintPartition(partition)
 
-	var tempSuperblock Structs.Superblock
 	// Leer el Superblock desde el archivo binario
-	if err := Utilities.ReadObject(file, &tempSuperblock, int64(TempMBR.Partitions[index].Start)); err != nil {
+	tempSuperblock, err := DiskManagement.ReadSuperblock(file, partition.Start)
+	if err != nil {
 		fmt.Println("Error: No se pudo leer el Superblock:", err)
-		return "Error: No se pudo leer el Superblock", err
+		return "", fmt.Errorf("no se pudo leer el Superblock: %v", err)
 	}
 
 	// Buscar el archivo de usuarios /users.txt -> retorna índice del Inodo
@@ -102,9 +96,9 @@ func Login(user string, pass string, id string) (string, error) {
 
 	var crrInode Structs.Inode
 	// Leer el Inodo desde el archivo binario
-	if err := Utilities.ReadObject(file, &crrInode, int64(tempSuperblock.S_inode_start+indexInode*int32(binary.Size(Structs.Inode{})))); err != nil {
+	if err := Utilities.ReadObject(file, &crrInode, tempSuperblock.S_inode_start+int64(indexInode)*int64(binary.Size(Structs.Inode{}))); err != nil {
 		fmt.Println("Error: No se pudo leer el Inodo:", err)
-		return "Error: No se pudo leer el Inodo", err
+		return "", fmt.Errorf("no se pudo leer el Inodo: %v", err)
 	}
 
 	// Leer datos del archivo
@@ -128,13 +122,18 @@ func Login(user string, pass string, id string) (string, error) {
 	// Imprimir información del Inodo
 	fmt.Println("Inode", crrInode.I_block)
 
-	// Si las credenciales son correctas y marcamos como logueado
-	if login {
-		fmt.Println("Usuario logueado con exito")
-		DiskManagement.MarkPartitionAsLoggedIn(id) // Marcar la partición como logueada
-		CurrentLoggedPartitionID = id
+	// Si las credenciales no son correctas no se inicia la sesión
+	if !login {
+		fmt.Println("Usuario o contraseña incorrectos")
+		return "", fmt.Errorf("usuario o contraseña incorrectos")
 	}
 
+	// Las credenciales son correctas y marcamos como logueado
+	fmt.Println("Usuario logueado con exito")
+	DiskManagement.MarkPartitionAsLoggedIn(id) // Marcar la partición como logueada
+	CurrentLoggedPartitionID = id
+	CurrentUser = user
+
 	fmt.Println("======End LOGIN======")
 	return "Usuario logueado con exito", nil
 }
@@ -187,7 +186,7 @@ func SarchInodeByPath(StepsPath []string, Inode Structs.Inode, file *os.File, te
 
 				var crrFolderBlock Structs.Folderblock
 				// Read object from bin file
-				if err := Utilities.ReadObject(file, &crrFolderBlock, int64(tempSuperblock.S_block_start+block*int32(binary.Size(Structs.Folderblock{})))); err != nil {
+				if err := Utilities.ReadObject(file, &crrFolderBlock, tempSuperblock.S_block_start+int64(block)*int64(binary.Size(Structs.Folderblock{}))); err != nil {
 					return -1
 				}
 
@@ -205,7 +204,7 @@ func SarchInodeByPath(StepsPath []string, Inode Structs.Inode, file *os.File, te
 							fmt.Println("NextInode======")
 							var NextInode Structs.Inode
 							// Read object from bin file
-							if err := Utilities.ReadObject(file, &NextInode, int64(tempSuperblock.S_inode_start+folder.B_inodo*int32(binary.Size(Structs.Inode{})))); err != nil {
+							if err := Utilities.ReadObject(file, &NextInode, tempSuperblock.S_inode_start+int64(folder.B_inodo)*int64(binary.Size(Structs.Inode{}))); err != nil {
 								return -1
 							}
 							return SarchInodeByPath(StepsPath, NextInode, file, tempSuperblock)
@@ -237,7 +236,7 @@ func GetInodeFileData(Inode Structs.Inode, file *os.File, tempSuperblock Structs
 		// Leer solo los bloques directos (0-12)
 		if index < 13 {
 			var crrFileBlock Structs.Fileblock
-			blockOffset := int64(tempSuperblock.S_block_start + block*int32(binary.Size(Structs.Fileblock{})))
+			blockOffset := tempSuperblock.S_block_start + int64(block)*int64(binary.Size(Structs.Fileblock{}))
 
 			// Leer el bloque del archivo
 			if err := Utilities.ReadObject(file, &crrFileBlock, blockOffset); err != nil {
@@ -279,13 +278,13 @@ func AppendToFileBlock(inode *Structs.Inode, newData string, file *os.File, supe
 	// Escribir el contenido actualizado en el bloque existente
 	var updatedFileBlock Structs.Fileblock
 	copy(updatedFileBlock.B_content[:], fullData)
-	if err := Utilities.WriteObject(file, updatedFileBlock, int64(superblock.S_block_start+inode.I_block[0]*int32(binary.Size(Structs.Fileblock{})))); err != nil {
+	if err := Utilities.WriteObject(file, updatedFileBlock, superblock.S_block_start+int64(inode.I_block[0])*int64(binary.Size(Structs.Fileblock{}))); err != nil {
 		return fmt.Errorf("error al escribir el bloque actualizado: %v", err)
 	}
 
 	// Actualizar el tamaño del inodo
 	inode.I_size = int32(len(fullData))
-	if err := Utilities.WriteObject(file, *inode, int64(superblock.S_inode_start+inode.I_block[0]*int32(binary.Size(Structs.Inode{})))); err != nil {
+	if err := Utilities.WriteObject(file, *inode, superblock.S_inode_start+int64(inode.I_block[0])*int64(binary.Size(Structs.Inode{}))); err != nil {
 		return fmt.Errorf("error al actualizar el inodo: %v", err)
 	}
 
@@ -316,16 +315,18 @@ func Logout() (string, error) {
 	if !sessionFound {
 		fmt.Println("Error: No hay una sesión activa actualmente.")
 		fmt.Println("======End LOGOUT======")
-		return "Error: No hay una sesión activa actualmente.", nil
+		return "", fmt.Errorf("no hay una sesión activa actualmente")
 	}
 
 	// Log out the user
 	err := DiskManagement.MarkPartitionAsLoggedOut(loggedOutPartitionID)
 	if err != nil {
 		fmt.Printf("Error al cerrar la sesión: %v\n", err)
-	} else {
-		fmt.Println("Sesión cerrada exitosamente.")
+		return "", fmt.Errorf("error al cerrar la sesión: %v", err)
 	}
+	CurrentLoggedPartitionID = ""
+	CurrentUser = ""
+	fmt.Println("Sesión cerrada exitosamente.")
 
 	fmt.Println("======End LOGOUT======")
 	return "Sesión cerrada exitosamente.", nil
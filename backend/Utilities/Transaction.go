@@ -0,0 +1,286 @@
+package Utilities
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// undoEntry guarda los bytes que había en un archivo antes de sobrescribirlos
+type undoEntry struct {
+	path     string
+	position int64
+	data     []byte
+}
+
+// transaction es el diario de deshacer de una ejecución atómica.
+// Antes de cada escritura se guardan los bytes que se van a sobrescribir; los archivos
+// creados durante la transacción se borran al deshacer y los eliminados se mueven a un respaldo.
+type transaction struct {
+	entries   []undoEntry
+	sizes     map[string]int64  // Tamaño original de cada archivo modificado
+	created   map[string]bool   // Archivos que no existían al iniciar la transacción
+	removed   map[string]string // Archivo eliminado -> copia de respaldo
+	backupDir string
+}
+
+// Transacción activa; nil cuando no se está en modo atómico
+var activeTransaction *transaction
+
+// BeginTransaction inicia el registro de cambios de los discos
+func BeginTransaction() error {
+	if activeTransaction != nil {
+		return errors.New("ya hay una transacción en curso")
+	}
+	activeTransaction = &transaction{
+		sizes:   make(map[string]int64),
+		created: make(map[string]bool),
+		removed: make(map[string]string),
+	}
+	return nil
+}
+
+// InTransaction indica si hay una transacción en curso
+func InTransaction() bool {
+	return activeTransaction != nil
+}
+
+// CommitTransaction confirma los cambios y descarta el diario y los respaldos
+func CommitTransaction() error {
+	tx := activeTransaction
+	if tx == nil {
+		return errors.New("no hay una transacción en curso")
+	}
+	activeTransaction = nil
+	if tx.backupDir != "" {
+		return os.RemoveAll(tx.backupDir)
+	}
+	return nil
+}
+
+// RollbackTransaction deshace todos los cambios registrados desde BeginTransaction
+func RollbackTransaction() error {
+	tx := activeTransaction
+	if tx == nil {
+		return errors.New("no hay una transacción en curso")
+	}
+	activeTransaction = nil
+
+	var errs []error
+
+	// Borrar los archivos creados y devolver los eliminados a su lugar
+	for path := range tx.created {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	for path, backup := range tx.removed {
+		if err := os.Rename(backup, path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Restaurar los bytes sobrescritos, del último cambio al primero
+	for i := len(tx.entries) - 1; i >= 0; i-- {
+		entry := tx.entries[i]
+		file, err := os.OpenFile(entry.path, os.O_RDWR, 0644)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, err := file.WriteAt(entry.data, entry.position); err != nil {
+			errs = append(errs, err)
+		}
+		file.Close()
+	}
+
+	// Las escrituras más allá del final del archivo se quitan devolviéndolo a su tamaño original
+	for path, size := range tx.sizes {
+		if err := os.Truncate(path, size); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if tx.backupDir != "" {
+		os.RemoveAll(tx.backupDir)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("no se pudieron deshacer todos los cambios: %v", errors.Join(errs...))
+	}
+	return nil
+}
+
+// recordWrite guarda los bytes que una escritura de size bytes va a sobrescribir. Si no puede
+// guardarlos devuelve el error: escribir sin poder deshacer rompería la transacción.
+func (tx *transaction) recordWrite(file *os.File, position int64, size int) error {
+	path := absolutePath(file.Name())
+
+	// Los archivos creados en la transacción se borran completos al deshacer
+	if tx.created[path] {
+		return nil
+	}
+
+	if _, ok := tx.sizes[path]; !ok {
+		info, err := file.Stat()
+		if err != nil {
+			fmt.Println("Err Transaction stat==", err)
+			return err
+		}
+		tx.sizes[path] = info.Size()
+	}
+
+	data := make([]byte, size)
+	n, err := file.ReadAt(data, position)
+	if err != nil && err != io.EOF {
+		fmt.Println("Err Transaction read==", err)
+		return err
+	}
+	if n == 0 {
+		// Todo queda más allá del final del archivo: lo resuelve el tamaño original
+		return nil
+	}
+	tx.entries = append(tx.entries, undoEntry{path: path, position: position, data: data[:n]})
+	return nil
+}
+
+// recordTruncate guarda el tamaño original y los bytes que se pierden si el archivo se acorta a size
+func (tx *transaction) recordTruncate(file *os.File, size int64) error {
+	path := absolutePath(file.Name())
+	if tx.created[path] {
+		return nil
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		fmt.Println("Err Transaction stat==", err)
+		return err
+	}
+	if _, ok := tx.sizes[path]; !ok {
+		tx.sizes[path] = info.Size()
+	}
+	if size < info.Size() {
+		return tx.recordWrite(file, size, int(info.Size()-size))
+	}
+	return nil
+}
+
+// recordCreate marca un archivo como creado durante la transacción
+func (tx *transaction) recordCreate(name string) {
+	tx.created[absolutePath(name)] = true
+}
+
+// absolutePath normaliza la ruta para que el mismo disco siempre tenga la misma llave
+func absolutePath(name string) string {
+	path, err := filepath.Abs(name)
+	if err != nil {
+		return name
+	}
+	return path
+}
+
+// RemoveFile elimina un archivo; dentro de una transacción lo mueve a un respaldo para poder restaurarlo
+func RemoveFile(name string) error {
+	tx := activeTransaction
+	if tx == nil {
+		return os.Remove(name)
+	}
+
+	path := absolutePath(name)
+	if tx.created[path] {
+		delete(tx.created, path)
+		return os.Remove(path)
+	}
+
+	if tx.backupDir == "" {
+		dir, err := os.MkdirTemp("", "mia-tx-")
+		if err != nil {
+			return err
+		}
+		tx.backupDir = dir
+	}
+	backup := filepath.Join(tx.backupDir, fmt.Sprintf("%d-%s", len(tx.removed), filepath.Base(path)))
+	if err := os.Rename(path, backup); err != nil {
+		return err
+	}
+	tx.removed[path] = backup
+	return nil
+}
+
+// ReplaceFile pone replacement en el lugar de name; dentro de una transacción el original
+// se mueve a un respaldo y el nuevo se trata como creado, así al deshacer vuelve el original
+func ReplaceFile(name string, replacement string) error {
+	tx := activeTransaction
+	if tx == nil {
+		return os.Rename(replacement, name)
+	}
+	if err := RemoveFile(name); err != nil {
+		return err
+	}
+	if err := os.Rename(replacement, name); err != nil {
+		return err
+	}
+	tx.recordCreate(name)
+	return nil
+}
+
+// MoveFile mueve name a target; dentro de una transacción deshacer lo devuelve a su lugar
+func MoveFile(name string, target string) error {
+	if err := renameFile(name, target); err != nil {
+		return err
+	}
+	tx := activeTransaction
+	if tx == nil {
+		return nil
+	}
+
+	path, moved := absolutePath(name), absolutePath(target)
+	// Un archivo que ya se movió en la transacción solo cambia de lugar en el diario
+	for original, current := range tx.removed {
+		if current == path {
+			if original == moved {
+				delete(tx.removed, original)
+			} else {
+				tx.removed[original] = moved
+			}
+			return nil
+		}
+	}
+	if tx.created[path] {
+		delete(tx.created, path)
+		tx.recordCreate(moved)
+		return nil
+	}
+	tx.removed[path] = moved
+	return nil
+}
+
+// renameFile renombra el archivo; si el destino está en otro sistema de archivos lo copia y borra el original
+func renameFile(name string, target string) error {
+	err := os.Rename(name, target)
+	if err == nil || !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	source, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	destination, err := os.OpenFile(target, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(destination, source); err != nil {
+		destination.Close()
+		os.Remove(target)
+		return err
+	}
+	if err := destination.Close(); err != nil {
+		os.Remove(target)
+		return err
+	}
+	source.Close()
+	return os.Remove(name)
+}
@@ -0,0 +1,238 @@
+package Utilities
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// snapshotDir devuelve el contenido de cada archivo dentro de dir, por ruta relativa
+func snapshotDir(t *testing.T, dir string) map[string][]byte {
+	t.Helper()
+	files := make(map[string][]byte)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		relative, _ := filepath.Rel(dir, path)
+		files[relative] = data
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// compareDirs informa cada archivo que falta, sobra o cambió respecto a want
+func compareDirs(t *testing.T, got map[string][]byte, want map[string][]byte) {
+	t.Helper()
+	for name, data := range want {
+		if current, ok := got[name]; !ok {
+			t.Errorf("falta el archivo %s", name)
+		} else if !bytes.Equal(current, data) {
+			t.Errorf("%s cambió: %d bytes %q..., se esperaban %d bytes %q...", name, len(current), current[:min(len(current), 16)], len(data), data[:min(len(data), 16)])
+		}
+	}
+	for name := range got {
+		if _, ok := want[name]; !ok {
+			t.Errorf("sobra el archivo %s", name)
+		}
+	}
+}
+
+// writeFiles crea los archivos de prueba en dir con su contenido
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// openFile abre un archivo de prueba para leer y escribir; se cierra al terminar el test
+func openFile(t *testing.T, path string) *os.File {
+	t.Helper()
+	file, err := OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+	return file
+}
+
+// changeFiles hace en dir un cambio de cada tipo que registra el diario
+func changeFiles(t *testing.T, dir string) {
+	t.Helper()
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	// Escrituras sobre la misma zona, más allá del final y después de acortar y alargar el archivo
+	disk := openFile(t, path("disco.mia"))
+	for _, step := range []struct {
+		data     string
+		position int64
+	}{{"XXXX", 2}, {"YYYYYY", 4}, {"fuera del final", 40}} {
+		if err := WriteObject(disk, []byte(step.data), step.position); err != nil {
+			t.Fatal(err)
+		}
+	}
+	shrunk := openFile(t, path("corto.mia"))
+	if err := TruncateFile(shrunk, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := TruncateFile(shrunk, 100); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteObject(shrunk, []byte("ZZ"), 50); err != nil {
+		t.Fatal(err)
+	}
+
+	// Un archivo nuevo, uno eliminado, uno reemplazado y uno movido
+	if err := CreateFile(path("nuevo/nuevo.mia")); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteObject(openFile(t, path("nuevo/nuevo.mia")), []byte("nuevo"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveFile(path("borrado.mia")); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateFile(path("reemplazo.tmp")); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteObject(openFile(t, path("reemplazo.tmp")), []byte("contenido nuevo"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReplaceFile(path("reemplazado.mia"), path("reemplazo.tmp")); err != nil {
+		t.Fatal(err)
+	}
+	if err := MoveFile(path("movido.mia"), path("papelera.mia")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+var testFiles = map[string]string{
+	"disco.mia":       "0123456789abcdef",
+	"corto.mia":       "contenido que se corta",
+	"borrado.mia":     "se elimina",
+	"reemplazado.mia": "contenido original",
+	"movido.mia":      "se mueve",
+}
+
+func TestRollbackTransaction(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, testFiles)
+	before := snapshotDir(t, dir)
+
+	if err := BeginTransaction(); err != nil {
+		t.Fatal(err)
+	}
+	changeFiles(t, dir)
+	if !InTransaction() {
+		t.Fatal("la transacción terminó antes de tiempo")
+	}
+	backups := activeTransaction.backupDir
+	if err := RollbackTransaction(); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Remove(filepath.Join(dir, "nuevo")) // CreateFile crea la carpeta, que no forma parte del diario
+	compareDirs(t, snapshotDir(t, dir), before)
+	if InTransaction() {
+		t.Error("sigue habiendo una transacción después de deshacer")
+	}
+	if _, err := os.Stat(backups); !os.IsNotExist(err) {
+		t.Errorf("no se borró la carpeta de respaldos %s", backups)
+	}
+}
+
+func TestCommitTransaction(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, testFiles)
+
+	if err := BeginTransaction(); err != nil {
+		t.Fatal(err)
+	}
+	changeFiles(t, dir)
+	backups := activeTransaction.backupDir
+	if err := CommitTransaction(); err != nil {
+		t.Fatal(err)
+	}
+
+	after := snapshotDir(t, dir)
+	want := map[string]string{
+		"disco.mia":       "01XXYYYYYY" + "abcdef",
+		"nuevo/nuevo.mia": "nuevo",
+		"reemplazado.mia": "contenido nuevo",
+		"papelera.mia":    "se mueve",
+	}
+	for name, content := range want {
+		if !strings.HasPrefix(string(after[name]), content) {
+			t.Errorf("%s = %q, se esperaba que empezara con %q", name, after[name], content)
+		}
+	}
+	for _, name := range []string{"borrado.mia", "movido.mia", "reemplazo.tmp"} {
+		if _, ok := after[name]; ok {
+			t.Errorf("%s sigue existiendo después de confirmar", name)
+		}
+	}
+	if len(after["corto.mia"]) != 100 || string(after["corto.mia"][:3]) != "con" || string(after["corto.mia"][50:52]) != "ZZ" {
+		t.Errorf("corto.mia = %q", after["corto.mia"])
+	}
+	if _, err := os.Stat(backups); !os.IsNotExist(err) {
+		t.Errorf("no se borró la carpeta de respaldos %s", backups)
+	}
+}
+
+// TestUnrecordedWrite revisa que una escritura o un truncado cuyo respaldo no se puede guardar
+// falle sin tocar el archivo
+func TestUnrecordedWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "disco.mia")
+	writeFiles(t, filepath.Dir(path), map[string]string{"disco.mia": "original"})
+
+	if err := BeginTransaction(); err != nil {
+		t.Fatal(err)
+	}
+	defer RollbackTransaction()
+
+	// Con el archivo cerrado no se pueden leer los bytes que se van a sobrescribir
+	file, err := OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	if err := WriteObject(file, []byte("nuevo"), 0); err == nil {
+		t.Error("WriteObject no falló sin poder guardar los bytes originales")
+	}
+	if err := TruncateFile(file, 2); err == nil {
+		t.Error("TruncateFile no falló sin poder guardar los bytes originales")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "original" {
+		t.Errorf("el archivo quedó con %q", data)
+	}
+}
+
+func TestNestedTransaction(t *testing.T) {
+	if err := BeginTransaction(); err != nil {
+		t.Fatal(err)
+	}
+	if err := BeginTransaction(); err == nil {
+		t.Error("se inició una transacción dentro de otra")
+	}
+	if err := CommitTransaction(); err != nil {
+		t.Fatal(err)
+	}
+	if err := CommitTransaction(); err == nil {
+		t.Error("se confirmó una transacción que no existe")
+	}
+	if err := RollbackTransaction(); err == nil {
+		t.Error("se deshizo una transacción que no existe")
+	}
+}
@@ -26,6 +26,11 @@ func CreateFile(name string) error {
 			return err
 		}
 		defer file.Close()
+
+		// En modo atómico el archivo nuevo se borra si hay que deshacer
+		if activeTransaction != nil {
+			activeTransaction.recordCreate(name)
+		}
 	}
 	return nil
 }
@@ -42,6 +47,12 @@ func OpenFile(name string) (*os.File, error) {
 
 // Funcion para escribir un objecto en un archivo binario
 func WriteObject(file *os.File, data interface{}, position int64) error {
+	// En modo atómico se guardan los bytes que se van a sobrescribir
+	if activeTransaction != nil {
+		if err := activeTransaction.recordWrite(file, position, binary.Size(data)); err != nil {
+			return err
+		}
+	}
 	file.Seek(position, 0)
 	err := binary.Write(file, binary.LittleEndian, data)
 	if err != nil {
@@ -51,6 +62,21 @@ func WriteObject(file *os.File, data interface{}, position int64) error {
 	return nil
 }
 
+// TruncateFile cambia el tamaño del archivo; lo que crece queda como hueco (se lee como ceros)
+func TruncateFile(file *os.File, size int64) error {
+	// En modo atómico se guardan los bytes que se van a cortar
+	if activeTransaction != nil {
+		if err := activeTransaction.recordTruncate(file, size); err != nil {
+			return err
+		}
+	}
+	if err := file.Truncate(size); err != nil {
+		fmt.Println("Err TruncateFile==", err)
+		return err
+	}
+	return nil
+}
+
 // Funcion para leer un objeto de un archivo binario
 func ReadObject(file *os.File, data interface{}, position int64) error {
 	file.Seek(position, 0)
@@ -102,6 +128,29 @@ func GetLetter(path string) (string, error) {
 	return pathToLetter[path], nil
 }
 
+// Carpeta de datos que reemplaza temporalmente a la configurada (ver SetDataDir)
+var dataDirOverride string
+
+// DataDir devuelve la carpeta donde el backend guarda sus propios datos (historial, etc.).
+// Se puede cambiar con la variable de entorno MIA_DATA_DIR.
+func DataDir() string {
+	if dataDirOverride != "" {
+		return dataDirOverride
+	}
+	if dir := os.Getenv("MIA_DATA_DIR"); dir != "" {
+		return dir
+	}
+	return "data"
+}
+
+// SetDataDir hace que DataDir devuelva dir hasta que se llame a la función que devuelve, que
+// vuelve a la carpeta anterior. Lo usa replay para no tocar la tabla de montajes ni la papelera reales.
+func SetDataDir(dir string) func() {
+	previous := dataDirOverride
+	dataDirOverride = dir
+	return func() { dataDirOverride = previous }
+}
+
 // createParentDirs crea las carpetas padre si no existen
 func CreateParentDirs(path string) error {
 	dir := filepath.Dir(path)
@@ -173,3 +222,54 @@ func SplitStringIntoChunks(s string) []string {
 	}
 	return chunks
 }
+
+// Levenshtein calcula la distancia de edición entre dos cadenas (inserciones, borrados y sustituciones)
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// ProgressFunc recibe el avance de una operación larga (por ejemplo el llenado de ceros de mkdisk)
+type ProgressFunc func(task string, done int64, total int64)
+
+// Manejador de progreso activo y último porcentaje reportado por tarea
+var progressHandler ProgressFunc
+var lastProgress = make(map[string]int64)
+
+// SetProgressHandler registra quién recibe el progreso; nil lo desactiva
+func SetProgressHandler(handler ProgressFunc) {
+	progressHandler = handler
+	lastProgress = make(map[string]int64)
+}
+
+// ReportProgress informa el avance de una tarea; solo notifica cuando cambia el porcentaje
+func ReportProgress(task string, done int64, total int64) {
+	if progressHandler == nil || total <= 0 {
+		return
+	}
+	percent := done * 100 / total
+	if last, ok := lastProgress[task]; ok && last == percent {
+		return
+	}
+	lastProgress[task] = percent
+	if done >= total {
+		delete(lastProgress, task)
+	}
+	progressHandler(task, done, total)
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"backend/Analyzer"
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Códigos de salida del modo -script
+const (
+	exitOK     = 0 // Todas las líneas terminaron bien
+	exitErrors = 1 // Al menos una línea terminó en error
+	exitUsage  = 2 // No se pudo leer el script
+)
+
+// Prompt del modo interactivo
+const replPrompt = "mia> "
+
+// runScriptFile ejecuta un script del host (o la entrada estándar con "-") e imprime cada resultado.
+// Devuelve el código de salida del proceso.
+func runScriptFile(path string, opts Analyzer.Options) int {
+	// Los resultados van a la salida estándar; el avance de mkdisk, convertdisk, etc., a la de errores
+	opts.OnProgress = func(task string, done int64, total int64) {
+		fmt.Fprintf(os.Stderr, "%s: %d%%\n", task, done*100/total)
+	}
+
+	var results []Analyzer.Result
+	if path == "-" {
+		source, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: no se pudo leer la entrada estándar:", err)
+			return exitUsage
+		}
+		results = Analyzer.RunScript(string(source), "stdin", opts)
+	} else {
+		var err error
+		results, err = Analyzer.RunFile(path, opts)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return exitUsage
+		}
+	}
+
+	code := exitOK
+	for _, res := range results {
+		if res.Status == Analyzer.StatusError {
+			fmt.Fprintln(os.Stderr, res.String())
+			code = exitErrors
+			continue
+		}
+		fmt.Println(res.String())
+	}
+	return code
+}
+
+// runREPL lee comandos de la terminal y los ejecuta uno por uno hasta exit o Ctrl+D.
+// Si la entrada no es una terminal se leen las líneas sin edición (por ejemplo, desde una tubería).
+func runREPL(opts Analyzer.Options) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if !replLine(os.Stdout, scanner.Text(), opts) {
+				return
+			}
+		}
+		return
+	}
+
+	// Modo crudo para que la terminal permita editar la línea y recorrer el historial con las flechas
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error: no se pudo preparar la terminal:", err)
+		return
+	}
+	defer term.Restore(fd, oldState)
+
+	terminal := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, replPrompt)
+	if width, height, err := term.GetSize(fd); err == nil && width > 0 {
+		terminal.SetSize(width, height)
+	}
+
+	// En la terminal clear sí tiene sentido: limpia la pantalla del usuario
+	Analyzer.ClearScreen = func() error {
+		_, err := terminal.Write([]byte("\033[H\033[2J"))
+		return err
+	}
+
+	fmt.Fprintln(terminal, "MIA - escriba help para ver los comandos, exit para salir")
+	for {
+		line, err := terminal.ReadLine()
+		if err != nil {
+			// io.EOF al presionar Ctrl+D
+			fmt.Fprintln(terminal)
+			return
+		}
+		keep := true
+		throughTerminal(terminal, func() { keep = replLine(os.Stdout, line, opts) })
+		if !keep {
+			return
+		}
+	}
+}
+
+// throughTerminal ejecuta run con la salida estándar redirigida a la terminal. Los comandos imprimen
+// con fmt.Println directo en os.Stdout y en modo crudo ese \n no vuelve al inicio de la línea;
+// term.Terminal sí lo convierte en \r\n. run también debe escribir su resultado en os.Stdout para
+// que quede después de lo que imprimió el comando. Termina cuando ya se escribió todo.
+func throughTerminal(terminal io.Writer, run func()) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		run()
+		return
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	done := make(chan struct{})
+	go func() {
+		io.Copy(terminal, reader)
+		reader.Close()
+		close(done)
+	}()
+	defer func() {
+		os.Stdout = stdout
+		writer.Close()
+		<-done
+	}()
+	run()
+}
+
+// replLine ejecuta una línea del modo interactivo; devuelve false si el usuario pidió salir
+func replLine(out io.Writer, line string, opts Analyzer.Options) bool {
+	trimmed := strings.TrimSpace(line)
+	switch strings.ToLower(trimmed) {
+	case "":
+		return true
+	case "exit", "quit":
+		return false
+	}
+	if strings.HasPrefix(trimmed, "#") {
+		fmt.Fprintln(out, trimmed)
+		return true
+	}
+
+	message, err := Analyzer.AnalyzerWithOptions(line, opts)
+	if message != "" {
+		fmt.Fprintln(out, message)
+	}
+	if err != nil {
+		fmt.Fprintln(out, "Error:", err)
+	}
+	return true
+}
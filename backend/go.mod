@@ -2,9 +2,13 @@ module backend
 
 go 1.22.6
 
+require (
+	github.com/gofiber/fiber/v2 v2.52.5
+	golang.org/x/term v0.24.0
+)
+
 require (
 	github.com/andybalholm/brotli v1.1.0 // indirect
-	github.com/gofiber/fiber/v2 v2.52.5 // indirect
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/klauspost/compress v1.17.9 // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
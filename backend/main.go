@@ -2,15 +2,83 @@ package main
 
 import (
 	"backend/Analyzer"
+	"backend/DiskManagement"
+	"flag"
 	"fmt"
 	"log"
-	"strings"
+	"os"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 )
 
 func main() {
+	// Sin banderas se inicia el servidor HTTP; -script y -repl usan la misma lógica desde la terminal
+	script := flag.String("script", "", "Ejecuta un script (\"-\" para la entrada estándar) y termina con código 0 si no hubo errores")
+	repl := flag.Bool("repl", false, "Inicia el modo interactivo en la terminal")
+	strict := flag.Bool("strict", false, "Rechaza parámetros desconocidos en vez de ignorarlos con una advertencia")
+	stopOnError := flag.Bool("stop-on-error", false, "Detiene el script en la primera línea que falla")
+	atomic := flag.Bool("atomic", false, "Deshace todos los cambios del script si alguna línea falla")
+	dryRun := flag.Bool("dry-run", false, "Valida y simula sin crear ni modificar discos")
+	dataDir := flag.String("data", "", "Carpeta de datos del backend (historial, tabla de montajes); por defecto $MIA_DATA_DIR o ./data")
+	idPrefix := flag.String("id-prefix", "", "Uno o dos dígitos al inicio de los IDs de montaje; por defecto $MIA_ID_PREFIX o 09")
+	idNumbering := flag.String("id-numbering", "", "Número de los IDs de montaje: slot o correlative; por defecto $MIA_ID_NUMBERING o slot")
+	trashRetention := flag.String("trash-retention", "", "Tiempo que rmdisk conserva los discos en la papelera (7d, 36h; 0 los conserva siempre); por defecto $MIA_TRASH_RETENTION o 7d")
+	flag.Parse()
+
+	if *dataDir != "" {
+		os.Setenv("MIA_DATA_DIR", *dataDir)
+	}
+	if *idPrefix != "" {
+		os.Setenv(DiskManagement.IDPrefixEnv, *idPrefix)
+	}
+	if *idNumbering != "" {
+		os.Setenv(DiskManagement.IDNumberingEnv, *idNumbering)
+	}
+	if *trashRetention != "" {
+		os.Setenv(DiskManagement.TrashRetentionEnv, *trashRetention)
+	}
+	if _, err := DiskManagement.CurrentIDScheme(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(2)
+	}
+	if _, err := DiskManagement.TrashRetention(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(2)
+	}
+
+	// Restaurar los montajes de la ejecución anterior para que sus IDs sigan funcionando
+	restored, err := DiskManagement.LoadMounts()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+	}
+	if restored != "" {
+		fmt.Fprint(os.Stderr, restored)
+	}
+
+	// Eliminar de la papelera los discos que ya pasaron la retención
+	purged, err := DiskManagement.PurgeTrash()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+	}
+	if purged != "" {
+		fmt.Fprint(os.Stderr, purged)
+	}
+
+	opts := Analyzer.DefaultOptions
+	opts.Strict = *strict
+	opts.StopOnError = *stopOnError
+	opts.Atomic = *atomic
+	opts.DryRun = *dryRun
+
+	if *script != "" {
+		os.Exit(runScriptFile(*script, opts))
+	}
+	if *repl {
+		runREPL(opts)
+		return
+	}
+
 	// Crear una nueva instancia de Fiber
 	app := fiber.New()
 
@@ -21,7 +89,11 @@ func main() {
 	app.Post("/analyze", func(c *fiber.Ctx) error {
 		// Estructura para recibir el JSON
 		type Request struct {
-			Command string `json:"command"`
+			Command     string `json:"command"`
+			Strict      bool   `json:"strict"`      // Opcional: rechazar parámetros desconocidos en vez de ignorarlos con una advertencia
+			StopOnError bool   `json:"stopOnError"` // Opcional: detener el script en la primera línea que falla
+			Atomic      bool   `json:"atomic"`      // Opcional: si alguna línea falla se deshacen todos los cambios
+			DryRun      bool   `json:"dryRun"`      // Opcional: solo validar y simular, sin tocar los discos
 		}
 
 		// Crear una instancia de Request
@@ -36,44 +108,45 @@ func main() {
 
 		// Obtener el comando del cuerpo de la solicitud
 		input := req.Command
+		opts := Analyzer.DefaultOptions
+		opts.Strict = req.Strict
+		opts.StopOnError = req.StopOnError
+		opts.Atomic = req.Atomic
+		opts.DryRun = req.DryRun
 		fmt.Println("input: ", input)
 
-		// Separar el comando en líneas
-		lines := strings.Split(input, "\n")
-
-		// Lista para acumular los resultados de salida
-		var results []string
-
-		// Analizar cada línea
-		for _, line := range lines {
-			// Ignorar líneas vacías
-			if strings.TrimSpace(line) == "" {
-				continue
-			}
-
-			// Si la línea comienza con "#", agregar el comentario a los resultados
-			if strings.HasPrefix(strings.TrimSpace(line), "#") {
-				results = append(results, line)
-				continue
-			}
-
-			// Llamar a la función Analyzer del paquete analyzer para analizar la línea
-			result, err := Analyzer.Analyzer(line)
-			if err != nil {
-				// Si hay un error, almacenar el mensaje de error en lugar del resultado
-				result = fmt.Sprintf("Error: %s", err.Error())
-			}
-
-			// Acumular los resultados
-			results = append(results, result)
-		}
+		// Analizar cada línea; cada resultado lleva su número de línea, estado y tiempo
+		lines := Analyzer.RunScript(input, Analyzer.InputName, opts)
 
-		// Devolver una respuesta JSON con la lista de resultados
+		// Devolver los resultados estructurados y, para los clientes existentes, la lista de textos
 		return c.JSON(fiber.Map{
-			"results": results,
+			"lines":   lines,
+			"results": Analyzer.Strings(lines),
 		})
 	})
 
+	// Misma ejecución que /analyze, enviando cada resultado por Server-Sent Events
+	app.Post("/analyze/stream", analyzeStream)
+
+	// Cancelar una ejecución en curso de /analyze/stream
+	app.Post("/analyze/cancel/:id", cancelStream)
+
+	// Particiones montadas y discos de una carpeta, en JSON para el frontend
+	app.Get("/mounted", func(c *fiber.Ctx) error {
+		return c.JSON(Analyzer.ListMounted())
+	})
+	app.Get("/disks", func(c *fiber.Ctx) error {
+		dir := c.Query("dir")
+		if dir == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "falta el parámetro dir"})
+		}
+		disks, err := Analyzer.ListDisks(dir)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(disks)
+	})
+
 	// Iniciar el servidor en el puerto 3000
 	log.Fatal(app.Listen(":3000"))
 }
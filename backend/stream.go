@@ -0,0 +1,155 @@
+package main
+
+import (
+	"backend/Analyzer"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Ejecuciones en curso de /analyze/stream, por id, para poder cancelarlas
+var (
+	runs      = make(map[string]chan struct{})
+	runsMutex sync.Mutex
+)
+
+// registerRun crea el canal de cancelación de una ejecución nueva y devuelve su id
+func registerRun() (string, chan struct{}) {
+	runsMutex.Lock()
+	defer runsMutex.Unlock()
+	id := strconv.FormatInt(time.Now().UnixNano(), 36)
+	cancel := make(chan struct{})
+	runs[id] = cancel
+	return id, cancel
+}
+
+// cancelRun cierra el canal de la ejecución; devuelve false si no existe o ya terminó
+func cancelRun(id string) bool {
+	runsMutex.Lock()
+	defer runsMutex.Unlock()
+	cancel, ok := runs[id]
+	if !ok {
+		return false
+	}
+	close(cancel)
+	delete(runs, id)
+	return true
+}
+
+// finishRun elimina la ejecución del registro si nadie la canceló antes
+func finishRun(id string) {
+	runsMutex.Lock()
+	defer runsMutex.Unlock()
+	delete(runs, id)
+}
+
+// eventWriter escribe eventos Server-Sent Events; si el cliente se desconecta cancela la ejecución
+type eventWriter struct {
+	w      *bufio.Writer
+	id     string
+	closed bool
+}
+
+// send escribe un evento con su nombre y su contenido en JSON
+func (e *eventWriter) send(event string, data interface{}) {
+	if e.closed {
+		return
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		fmt.Println("Error al serializar el evento:", err)
+		return
+	}
+	fmt.Fprintf(e.w, "event: %s\ndata: %s\n\n", event, payload)
+	if err := e.w.Flush(); err != nil {
+		// El cliente cerró la conexión: ya no hay a quién enviarle nada
+		e.closed = true
+		cancelRun(e.id)
+	}
+}
+
+// analyzeStream ejecuta el script igual que /analyze pero envía cada resultado en cuanto termina.
+// Eventos: start (id de la ejecución), progress (avance de mkdisk/mkfs), result (un Result) y done (resumen).
+func analyzeStream(c *fiber.Ctx) error {
+	type Request struct {
+		Command     string `json:"command"`
+		Strict      bool   `json:"strict"`
+		StopOnError bool   `json:"stopOnError"`
+		Atomic      bool   `json:"atomic"`
+		DryRun      bool   `json:"dryRun"`
+	}
+
+	var req Request
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid JSON",
+		})
+	}
+
+	opts := Analyzer.DefaultOptions
+	opts.Strict = req.Strict
+	opts.StopOnError = req.StopOnError
+	opts.Atomic = req.Atomic
+	opts.DryRun = req.DryRun
+	input := req.Command
+	fmt.Println("input (stream): ", input)
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		id, cancel := registerRun()
+		defer finishRun(id)
+
+		events := &eventWriter{w: w, id: id}
+		events.send("start", fiber.Map{"id": id})
+
+		errors := 0
+		opts.Cancel = cancel
+		opts.OnResult = func(res Analyzer.Result) {
+			if res.Status == Analyzer.StatusError {
+				errors++
+			}
+			events.send("result", res)
+		}
+		opts.OnProgress = func(task string, done int64, total int64) {
+			events.send("progress", fiber.Map{
+				"task":    task,
+				"done":    done,
+				"total":   total,
+				"percent": done * 100 / total,
+			})
+		}
+
+		results := Analyzer.RunScript(input, Analyzer.InputName, opts)
+
+		// En modo atómico los resultados ya enviados pudieron deshacerse al final
+		rolledBack := false
+		for _, res := range results {
+			rolledBack = rolledBack || res.RolledBack
+		}
+		events.send("done", fiber.Map{
+			"results":    len(results),
+			"errors":     errors,
+			"cancelled":  opts.Cancelled(),
+			"rolledBack": rolledBack,
+		})
+	})
+	return nil
+}
+
+// cancelStream cancela una ejecución de /analyze/stream; el script se detiene antes de su siguiente línea
+func cancelStream(c *fiber.Ctx) error {
+	if !cancelRun(c.Params("id")) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "No existe una ejecución en curso con ese id",
+		})
+	}
+	return c.JSON(fiber.Map{"cancelled": true})
+}
@@ -1,5 +1,5 @@
 mkdisk -size=2 -unit=M -fit=WF -path="/home/juanjo/disks/DiscoLab.mia"
-rmdisk -path="/home/juanjo/disks/discolab.mia"
+rmdisk -path="/home/juanjo/disks/discolab.mia" -confirm
 fdisk -size=300 -type=P -unit=K -fit=B -name="Particion1" -path="/home/juanjo/disks/DiscoLab.mia"
 fdisk -size=100 -type=P -unit=K -fit=F -name="Particion2" -path="/home/juanjo/disks/DiscoLab.mia"
 fdisk -size=100 -type=E -unit=K -fit=B -name="ParticionE" -path="/home/juanjo/disks/DiscoLab.mia" 